@@ -0,0 +1,356 @@
+package gomodio
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheablePaths are the endpoints whose responses are cached
+var cacheablePaths = map[string]bool{
+	"/games/{game_id}":                     true,
+	"/games/{game_id}/tags":                true,
+	"/games/{game_id}/mods/{mod_id}":       true,
+	"/games/{game_id}/mods/{mod_id}/stats": true,
+}
+
+// CacheEntry is a cached response body and what is needed to revalidate it
+type CacheEntry struct {
+	Body         []byte    `json:"body"`
+	ContentType  string    `json:"content_type"`
+	ETag         string    `json:"etag"`
+	LastModified string    `json:"last_modified"`
+	Expires      time.Time `json:"expires"`
+	// GameID and ModID are what the response belongs to. ModID is 0 for game responses
+	GameID int `json:"game_id"`
+	ModID  int `json:"mod_id"`
+}
+
+// Cache stores responses of GetGame, GetMod, GetGameTagOptions and GetModStats.
+// Implementations must be safe for concurrent use
+type Cache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, e *CacheEntry)
+	// InvalidateMod removes every entry of a mod
+	InvalidateMod(modID int)
+	// InvalidateGame removes the entries of a game that belong to none of its mods
+	InvalidateGame(gameID int)
+}
+
+// SetCache makes the User serve cacheable responses from c until they expire. Responses
+// carrying a date_expires are kept until then, others for ttl. Expired entries are
+// revalidated with If-None-Match and If-Modified-Since. A nil c disables caching
+func (u *User) SetCache(c Cache, ttl time.Duration) {
+	u.cache = c
+	u.cacheTTL = ttl
+}
+
+// InvalidateMod removes a mod's responses from the User's cache
+func (u *User) InvalidateMod(modID int) {
+	if u.cache != nil {
+		u.cache.InvalidateMod(modID)
+	}
+}
+
+// InvalidateGame removes a game's responses, such as GetGame and GetGameTagOptions, from the User's cache
+func (u *User) InvalidateGame(gameID int) {
+	if u.cache != nil {
+		u.cache.InvalidateGame(gameID)
+	}
+}
+
+// doCached sends req through the User's cache. Successful writes to a mod invalidate
+// its entries, and writes to a game outside its mods invalidate the game's entries
+func (u *User) doCached(client *http.Client, req *http.Request) (*http.Response, error) {
+	if req.Method != "GET" || !cacheablePaths[pathTemplate(req.URL)] {
+		resp, err := u.send(client, req)
+		if err == nil && req.Method != "GET" && resp.StatusCode < 300 {
+			if id := pathID(req.URL, "mods"); id != 0 {
+				u.cache.InvalidateMod(id)
+			} else if id := pathID(req.URL, "games"); id != 0 {
+				u.cache.InvalidateGame(id)
+			}
+		}
+		return resp, err
+	}
+	key := cacheKey(req)
+	entry, found := u.cache.Get(key)
+	if found && time.Now().Before(entry.Expires) {
+		return entry.response(req), nil
+	}
+	if found {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+	resp, err := u.send(client, req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotModified && found:
+		entry.Expires = u.cacheExpiry(entry.Body)
+		u.cache.Set(key, entry)
+		return entry.response(req), nil
+	case resp.StatusCode == http.StatusOK:
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+		u.cache.Set(key, &CacheEntry{
+			Body:         b,
+			ContentType:  resp.Header.Get("Content-Type"),
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Expires:      u.cacheExpiry(b),
+			GameID:       pathID(req.URL, "games"),
+			ModID:        pathID(req.URL, "mods"),
+		})
+	}
+	return resp, nil
+}
+
+// cacheExpiry returns when a response body expires, from its date_expires or the User's TTL.
+// A date_expires already past, as on a revalidated body, falls back to the TTL
+func (u *User) cacheExpiry(body []byte) time.Time {
+	now := time.Now()
+	var v struct {
+		DateExpires Timestamp `json:"date_expires"`
+	}
+	if json.Unmarshal(body, &v) == nil && v.DateExpires.Time().After(now) {
+		return v.DateExpires.Time()
+	}
+	return now.Add(u.cacheTTL)
+}
+
+// response builds a 200 response serving the entry's body
+func (e *CacheEntry) response(req *http.Request) *http.Response {
+	h := http.Header{}
+	if e.ContentType != "" {
+		h.Set("Content-Type", e.ContentType)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          ioutil.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// cacheKey identifies a request by its URL without api_key and the headers that change the response
+func cacheKey(req *http.Request) string {
+	u := *req.URL
+	q := u.Query()
+	q.Del("api_key")
+	u.RawQuery = q.Encode()
+	key := u.String() + "|" + req.Header.Get("Accept-Language") + "|" + req.Header.Get("X-Modio-Platform") + "|" + req.Header.Get("X-Modio-Portal")
+	if auth := req.Header.Get("Authorization"); auth != "" {
+		sum := sha256.Sum256([]byte(auth))
+		key += "|" + hex.EncodeToString(sum[:8])
+	}
+	return key
+}
+
+// pathID returns the ID following collection in a request path, 0 when there is none
+func pathID(u *url.URL, collection string) int {
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == collection {
+			id, err := strconv.Atoi(segments[i+1])
+			if err == nil {
+				return id
+			}
+		}
+	}
+	return 0
+}
+
+// MemoryCache is an in-memory least recently used Cache holding at most maxBytes of bodies
+type MemoryCache struct {
+	mu       sync.Mutex
+	maxBytes int
+	size     int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewMemoryCache returns a MemoryCache evicting the least recently used entries beyond maxBytes
+func NewMemoryCache(maxBytes int) *MemoryCache {
+	return &MemoryCache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+// Get returns the entry stored under key
+func (c *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	e := *el.Value.(*memoryCacheItem).entry
+	return &e, true
+}
+
+// Set stores e under key, evicting old entries to stay within the size cap
+func (c *MemoryCache) Set(key string, e *CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+	if len(e.Body) > c.maxBytes {
+		return
+	}
+	c.entries[key] = c.order.PushFront(&memoryCacheItem{key: key, entry: e})
+	c.size += len(e.Body)
+	for c.size > c.maxBytes {
+		c.remove(c.order.Back())
+	}
+}
+
+// InvalidateMod removes every entry of a mod
+func (c *MemoryCache) InvalidateMod(modID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, el := range c.entries {
+		if el.Value.(*memoryCacheItem).entry.ModID == modID {
+			c.remove(el)
+		}
+	}
+}
+
+// InvalidateGame removes the entries of a game that belong to none of its mods
+func (c *MemoryCache) InvalidateGame(gameID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, el := range c.entries {
+		e := el.Value.(*memoryCacheItem).entry
+		if e.GameID == gameID && e.ModID == 0 {
+			c.remove(el)
+		}
+	}
+}
+
+func (c *MemoryCache) remove(el *list.Element) {
+	item := c.order.Remove(el).(*memoryCacheItem)
+	delete(c.entries, item.key)
+	c.size -= len(item.entry.Body)
+}
+
+// DiskCache is a Cache keeping one file per entry in a directory. Write failures
+// are ignored, leaving the response uncached
+type DiskCache struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewDiskCache returns a DiskCache storing entries in dir, which is created if needed
+func NewDiskCache(dir string) (*DiskCache, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// Get returns the entry stored under key
+func (c *DiskCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	matches, _ := filepath.Glob(filepath.Join(c.dir, "*-*-"+hashKey(key)+".json"))
+	if len(matches) == 0 {
+		return nil, false
+	}
+	b, err := ioutil.ReadFile(matches[0])
+	if err != nil {
+		return nil, false
+	}
+	var e CacheEntry
+	if json.Unmarshal(b, &e) != nil {
+		return nil, false
+	}
+	return &e, true
+}
+
+// Set stores e under key
+func (c *DiskCache) Set(key string, e *CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	// Files are named after the game and mod so invalidating needn't read them
+	path := filepath.Join(c.dir, strconv.Itoa(e.GameID)+"-"+strconv.Itoa(e.ModID)+"-"+hashKey(key)+".json")
+	tmp, err := ioutil.TempFile(c.dir, ".entry-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(b)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if os.Rename(tmp.Name(), path) != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// InvalidateMod removes every entry of a mod
+func (c *DiskCache) InvalidateMod(modID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removeMatching("*-" + strconv.Itoa(modID) + "-*.json")
+}
+
+// InvalidateGame removes the entries of a game that belong to none of its mods
+func (c *DiskCache) InvalidateGame(gameID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removeMatching(strconv.Itoa(gameID) + "-0-*.json")
+}
+
+func (c *DiskCache) removeMatching(pattern string) {
+	matches, _ := filepath.Glob(filepath.Join(c.dir, pattern))
+	for _, m := range matches {
+		os.Remove(m)
+	}
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,200 @@
+package gomodio
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheLRU(t *testing.T) {
+	c := NewMemoryCache(10)
+	c.Set("a", &CacheEntry{Body: []byte("aaaa")})
+	c.Set("b", &CacheEntry{Body: []byte("bbbb")})
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a missing")
+	}
+	// a was used last, so b is evicted to make room
+	c.Set("c", &CacheEntry{Body: []byte("cccc")})
+	if _, ok := c.Get("b"); ok {
+		t.Error("least recently used entry b was not evicted")
+	}
+	for _, k := range []string{"a", "c"} {
+		if _, ok := c.Get(k); !ok {
+			t.Errorf("%s evicted", k)
+		}
+	}
+	if c.size != 8 {
+		t.Errorf("size = %d, want 8", c.size)
+	}
+	// replacing an entry does not count it twice
+	c.Set("a", &CacheEntry{Body: []byte("aa")})
+	if c.size != 6 {
+		t.Errorf("size after replace = %d, want 6", c.size)
+	}
+	// entries larger than the cap are not stored
+	c.Set("big", &CacheEntry{Body: make([]byte, 11)})
+	if _, ok := c.Get("big"); ok {
+		t.Error("entry over the size cap was stored")
+	}
+}
+
+func TestMemoryCacheInvalidate(t *testing.T) {
+	c := NewMemoryCache(1 << 10)
+	c.Set("game", &CacheEntry{GameID: 1})
+	c.Set("mod1", &CacheEntry{GameID: 1, ModID: 1})
+	c.Set("mod12", &CacheEntry{GameID: 1, ModID: 12})
+	c.InvalidateMod(1)
+	if _, ok := c.Get("mod1"); ok {
+		t.Error("mod 1 not invalidated")
+	}
+	c.InvalidateGame(1)
+	if _, ok := c.Get("game"); ok {
+		t.Error("game 1 not invalidated")
+	}
+	if _, ok := c.Get("mod12"); !ok {
+		t.Error("mod 12 invalidated")
+	}
+}
+
+func TestDiskCacheInvalidate(t *testing.T) {
+	c, err := NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Set("game1", &CacheEntry{GameID: 1, Body: []byte("g")})
+	c.Set("game11", &CacheEntry{GameID: 11, Body: []byte("g")})
+	c.Set("mod1", &CacheEntry{GameID: 1, ModID: 1, Body: []byte("m")})
+	c.Set("mod12", &CacheEntry{GameID: 1, ModID: 12, Body: []byte("m")})
+	c.Set("mod21", &CacheEntry{GameID: 1, ModID: 21, Body: []byte("m")})
+
+	e, ok := c.Get("mod1")
+	if !ok || string(e.Body) != "m" {
+		t.Fatalf("Get(mod1) = %+v, %v", e, ok)
+	}
+	c.InvalidateMod(1)
+	if _, ok := c.Get("mod1"); ok {
+		t.Error("mod 1 not invalidated")
+	}
+	for _, k := range []string{"mod12", "mod21", "game1"} {
+		if _, ok := c.Get(k); !ok {
+			t.Errorf("%s invalidated with mod 1", k)
+		}
+	}
+	c.InvalidateGame(1)
+	if _, ok := c.Get("game1"); ok {
+		t.Error("game 1 not invalidated")
+	}
+	for _, k := range []string{"game11", "mod12"} {
+		if _, ok := c.Get(k); !ok {
+			t.Errorf("%s invalidated with game 1", k)
+		}
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	key := func(url, lang, token string) string {
+		req, _ := http.NewRequest("GET", url, nil)
+		if lang != "" {
+			req.Header.Set("Accept-Language", lang)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return cacheKey(req)
+	}
+	base := key("https://api.mod.io/v1/games/1?api_key=a", "", "")
+	if got := key("https://api.mod.io/v1/games/1?api_key=b", "", ""); got != base {
+		t.Errorf("api_key changes the key: %q != %q", got, base)
+	}
+	for name, other := range map[string]string{
+		"path":     key("https://api.mod.io/v1/games/2?api_key=a", "", ""),
+		"query":    key("https://api.mod.io/v1/games/1?api_key=a&_limit=5", "", ""),
+		"language": key("https://api.mod.io/v1/games/1?api_key=a", "ja", ""),
+		"token":    key("https://api.mod.io/v1/games/1?api_key=a", "", "t"),
+	} {
+		if other == base {
+			t.Errorf("%s does not change the key", name)
+		}
+	}
+	if got := key("https://api.mod.io/v1/games/1", "", "secret-token"); strings.Contains(got, "secret-token") {
+		t.Error("key contains the bearer token")
+	}
+}
+
+func TestCacheRevalidation(t *testing.T) {
+	var hits, notModified int32
+	expired := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"mod_id":2,"date_expires":` + expired + `}`))
+	}))
+	defer srv.Close()
+
+	cache := NewMemoryCache(1 << 10)
+	user := NewUser("key", "")
+	user.SetCache(cache, time.Hour)
+	get := func() string {
+		req, _ := user.newRequest("GET", srv.URL+"/v1/games/1/mods/2/stats?api_key=key", nil)
+		resp, err := user.do(&http.Client{}, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := ioutil.ReadAll(resp.Body)
+		if resp.StatusCode != 200 {
+			t.Fatalf("status = %d", resp.StatusCode)
+		}
+		return string(b)
+	}
+	first := get()
+	// expire the entry so the second call revalidates
+	for _, el := range cache.entries {
+		el.Value.(*memoryCacheItem).entry.Expires = time.Now().Add(-time.Minute)
+	}
+	if got := get(); got != first {
+		t.Errorf("revalidated body = %q, want %q", got, first)
+	}
+	if notModified != 1 {
+		t.Fatalf("304 responses = %d, want 1", notModified)
+	}
+	// the body's date_expires has passed, so after a 304 the entry is fresh for the TTL instead
+	get()
+	if hits != 2 {
+		t.Errorf("requests = %d, want 2", hits)
+	}
+}
+
+func TestCacheInvalidatedByGameWrite(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer srv.Close()
+
+	user := NewUser("key", "")
+	user.SetCache(NewMemoryCache(1<<10), time.Hour)
+	send := func(method, path string) {
+		req, _ := user.newRequest(method, srv.URL+path, nil)
+		if _, err := user.do(&http.Client{}, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	send("GET", "/v1/games/1/tags")
+	send("GET", "/v1/games/1/tags")
+	send("POST", "/v1/games/1/tags")
+	send("GET", "/v1/games/1/tags")
+	if hits != 3 {
+		t.Errorf("requests = %d, want 3", hits)
+	}
+}
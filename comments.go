@@ -1,7 +1,10 @@
 package gomodio
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -22,53 +25,44 @@ type Comments struct {
 
 // Comment struct representing single comment objects
 type Comment struct {
-	ID    int `json:"id"`
-	ModID int `json:"mod_id"`
-	User  struct {
-		ID         int    `json:"id"`
-		NameID     string `json:"name_id"`
-		Username   string `json:"username"`
-		DateOnline int    `json:"date_online"`
-		Avatar     struct {
-			Filename     string `json:"filename"`
-			Original     string `json:"original"`
-			Thumb50X50   string `json:"thumb_50x50"`
-			Thumb100X100 string `json:"thumb_100x100"`
-		} `json:"avatar"`
-		Timezone   string `json:"timezone"`
-		Language   string `json:"language"`
-		ProfileURL string `json:"profile_url"`
-	} `json:"user"`
-	DateAdded      int    `json:"date_added"`
-	ReplyID        int    `json:"reply_id"`
-	ThreadPosition string `json:"thread_position"`
-	Karma          int    `json:"karma"`
-	KarmaGuest     int    `json:"karma_guest"`
-	Content        string `json:"content"`
+	ID             int         `json:"id"`
+	ModID          int         `json:"mod_id"`
+	User           UserProfile `json:"user"`
+	DateAdded      Timestamp   `json:"date_added"`
+	ReplyID        int         `json:"reply_id"`
+	ThreadPosition string      `json:"thread_position"`
+	Karma          int         `json:"karma"`
+	KarmaGuest     int         `json:"karma_guest"`
+	Content        string      `json:"content"`
 }
 
 // DeleteModComment deletes an existing mod comment
 func DeleteModComment(commentID, modID, gameID int, user *User) (err error) {
-	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("DELETE", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/comments", nil)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return user.deleteModComment(context.Background(), commentID, modID, gameID)
+}
+
+// deleteModComment is DeleteModComment bound to ctx
+func (user *User) deleteModComment(ctx context.Context, commentID, modID, gameID int) (err error) {
+	err = user.requireToken()
 	if err != nil {
 		return err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
+	client := http.Client{Timeout: time.Duration(5 * time.Second)}
+	req, err := user.newRequestWithContext(ctx, "DELETE", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/comments/"+strconv.Itoa(commentID), nil)
+	if err != nil {
+		return err
 	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
-	if resp.StatusCode != 201 {
+	if resp.StatusCode != 204 {
 		var errObj ErrorCase
 		e := json.Unmarshal(body, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return e
 		}
 		return HandleResponseError(errObj)
 	}
@@ -80,19 +74,26 @@ func DeleteModComment(commentID, modID, gameID int, user *User) (err error) {
 
 // UpdateModComment updates an existing mod comment
 func UpdateModComment(content string, commentID, modID, gameID int, user *User) (res *Comment, err error) {
+	err = user.requireToken()
+	if err != nil {
+		return nil, err
+	}
+	if content == "" {
+		return nil, errors.New("content is required")
+	}
+	err = checkLength("content", &content, maxCommentLength)
+	if err != nil {
+		return nil, err
+	}
 	queryBody := url.Values{
 		"content": {content},
 	}
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("PUT", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/comments", strings.NewReader(queryBody.Encode()))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequest("PUT", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/comments/"+strconv.Itoa(commentID), strings.NewReader(queryBody.Encode()))
 	if err != nil {
 		return nil, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return nil, err
 	}
@@ -117,32 +118,36 @@ func UpdateModComment(content string, commentID, modID, gameID int, user *User)
 }
 
 // AddModComment adds a mod comment
-func (user *User) AddModComment(content string, modID, gameID int, options map[string]string) (res *Comment, err error) {
-	var queryBody url.Values
+func (user *User) AddModComment(content string, modID, gameID int, options *AddModCommentOptions) (res *Comment, err error) {
+	err = user.requireToken()
+	if err != nil {
+		return nil, err
+	}
+	if content == "" {
+		return nil, errors.New("content is required")
+	}
+	err = checkLength("content", &content, maxCommentLength)
+	if err != nil {
+		return nil, err
+	}
+	queryBody := url.Values{}
 	if options != nil {
-		queryBody = ParseArgsBody(options)
-	} else {
-		queryBody = url.Values{
-			"api_key": {user.APIKey()},
-			"content": {content},
-		}
+		queryBody = options.values()
 	}
+	queryBody.Set("api_key", user.APIKey())
+	queryBody.Set("content", content)
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/comments", strings.NewReader(queryBody.Encode()))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/comments", strings.NewReader(queryBody.Encode()))
 	if err != nil {
 		return nil, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
-	if resp.StatusCode != 200 {
+	if resp.StatusCode != 201 {
 		var errObj ErrorCase
 		e := json.Unmarshal(body, &errObj)
 		if e != nil {
@@ -163,15 +168,11 @@ func (user *User) AddModComment(content string, modID, gameID int, options map[s
 // GetModComment searches for a mod comment specifically
 func (user *User) GetModComment(commentID int, modID int, gameID int) (res *Comment, err error) {
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/comments/"+strconv.Itoa(commentID)+"?api_key="+user.APIKey(), nil)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/comments/"+strconv.Itoa(commentID)+"?api_key="+user.APIKey(), nil)
 	if err != nil {
 		return nil, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return nil, err
 	}
@@ -197,6 +198,11 @@ func (user *User) GetModComment(commentID int, modID int, gameID int) (res *Comm
 
 // GetModComments searches for mod comments
 func (user *User) GetModComments(modID int, gameID int, options map[string]string) (res *Comments, err error) {
+	return user.getModComments(context.Background(), modID, gameID, options)
+}
+
+// getModComments is GetModComments bound to ctx
+func (user *User) getModComments(ctx context.Context, modID int, gameID int, options map[string]string) (res *Comments, err error) {
 	var queryStr string
 	if options == nil {
 		queryStr = "api_key=" + user.APIKey()
@@ -205,25 +211,21 @@ func (user *User) GetModComments(modID int, gameID int, options map[string]strin
 		queryStr = ParseArgsGet(options)
 	}
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/comments"+"?"+queryStr, nil)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequestWithContext(ctx, "GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/comments"+"?"+queryStr, nil)
 	if err != nil {
 		return nil, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
-	if resp.StatusCode != 201 {
+	if resp.StatusCode != 200 {
 		var errObj ErrorCase
 		e := json.Unmarshal(body, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return nil, e
 		}
 		return nil, HandleResponseError(errObj)
 	}
@@ -236,3 +238,64 @@ func (user *User) GetModComments(modID int, gameID int, options map[string]strin
 	}
 	return res, nil
 }
+
+// ErrAlreadyVoted is returned by AddCommentKarma when the user has already cast the same vote
+var ErrAlreadyVoted = errors.New("already voted on comment")
+
+// ErrCannotVoteOwnComment is returned by AddCommentKarma when the user wrote the comment
+var ErrCannotVoteOwnComment = errors.New("cannot vote on own comment")
+
+// error_ref values mod.io sends when a karma vote is rejected
+const (
+	errorRefCannotVoteOwnComment = 15059
+	errorRefAlreadyVoted         = 15060
+)
+
+// AddCommentKarma upvotes a comment when positive is true and downvotes it otherwise,
+// returning the comment with its updated karma
+func (user *User) AddCommentKarma(commentID, modID, gameID int, positive bool) (res *Comment, err error) {
+	err = user.requireToken()
+	if err != nil {
+		return nil, err
+	}
+	karma := "-1"
+	if positive {
+		karma = "1"
+	}
+	queryBody := url.Values{
+		"karma": {karma},
+	}
+	client := http.Client{Timeout: time.Duration(5 * time.Second)}
+	req, err := user.newRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/comments/"+strconv.Itoa(commentID)+"/karma", strings.NewReader(queryBody.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := user.do(&client, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 201 && resp.StatusCode != 200 {
+		var errObj ErrorCase
+		e := json.Unmarshal(body, &errObj)
+		if e != nil {
+			return nil, e
+		}
+		switch errObj.Error.Code {
+		case errorRefAlreadyVoted:
+			return nil, fmt.Errorf("%w: %v", ErrAlreadyVoted, HandleResponseError(errObj))
+		case errorRefCannotVoteOwnComment:
+			return nil, fmt.Errorf("%w: %v", ErrCannotVoteOwnComment, HandleResponseError(errObj))
+		}
+		return nil, HandleResponseError(errObj)
+	}
+	err = json.Unmarshal(body, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,49 @@
+package gomodio
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestAddCommentKarma(t *testing.T) {
+	tests := []struct {
+		name     string
+		positive bool
+		status   int
+		body     string
+		wantSent string
+		want     error
+	}{
+		{"upvote", true, 201, `{"id":3,"karma":5}`, "1", nil},
+		{"downvote", false, 201, `{"id":3,"karma":3}`, "-1", nil},
+		{"own comment", true, 403, `{"error":{"code":403,"error_ref":15059,"message":"cannot add karma to own comment"}}`, "1", ErrCannotVoteOwnComment},
+		{"already voted", true, 403, `{"error":{"code":403,"error_ref":15060,"message":"already added karma"}}`, "1", ErrAlreadyVoted},
+	}
+	for _, tt := range tests {
+		var sent string
+		user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v1/games/1/mods/2/comments/3/karma" {
+				t.Errorf("%s: path = %s", tt.name, r.URL.Path)
+			}
+			r.ParseForm()
+			sent = r.PostForm.Get("karma")
+			w.WriteHeader(tt.status)
+			w.Write([]byte(tt.body))
+		})
+		user.SetOAuth2Token("token")
+		c, err := user.AddCommentKarma(3, 2, 1, tt.positive)
+		if sent != tt.wantSent {
+			t.Errorf("%s: karma = %q, want %q", tt.name, sent, tt.wantSent)
+		}
+		if tt.want == nil {
+			if err != nil || c == nil || c.ID != 3 {
+				t.Errorf("%s: comment = %+v, error = %v", tt.name, c, err)
+			}
+			continue
+		}
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
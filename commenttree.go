@@ -0,0 +1,153 @@
+package gomodio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxCommentDepth is the deepest level of replies mod.io allows. Top level comments are depth 1
+const maxCommentDepth = 3
+
+// commentPageSize is the number of comments requested per page when fetching every comment
+const commentPageSize = 100
+
+// CommentNode is a comment and the replies made to it, ordered by thread position
+type CommentNode struct {
+	Comment  Comment
+	Children []*CommentNode
+}
+
+// Depth returns how deeply the comment is nested, 1 for a top level comment
+func (c *Comment) Depth() int {
+	if c.ThreadPosition == "" {
+		return 1
+	}
+	return strings.Count(c.ThreadPosition, ".") + 1
+}
+
+// GetAllModComments fetches every page of a mod's comments
+func (user *User) GetAllModComments(modID, gameID int, options map[string]string) ([]Comment, error) {
+	return user.getAllModComments(context.Background(), modID, gameID, options)
+}
+
+// getAllModComments is GetAllModComments bound to ctx
+func (user *User) getAllModComments(ctx context.Context, modID, gameID int, options map[string]string) ([]Comment, error) {
+	var all []Comment
+	for offset := 0; ; offset += commentPageSize {
+		query := map[string]string{}
+		for k, v := range options {
+			query[k] = v
+		}
+		query["_limit"] = strconv.Itoa(commentPageSize)
+		query["_offset"] = strconv.Itoa(offset)
+		page, err := user.getModComments(ctx, modID, gameID, query)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Data...)
+		if len(page.Data) == 0 || offset+len(page.Data) >= page.ResultTotal {
+			return all, nil
+		}
+	}
+}
+
+// GetModCommentTree fetches every comment of a mod and nests replies under their parents.
+// Replies whose parent is missing are kept at the top level
+func (user *User) GetModCommentTree(modID, gameID int) ([]*CommentNode, error) {
+	comments, err := user.GetAllModComments(modID, gameID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return BuildCommentTree(comments), nil
+}
+
+// BuildCommentTree nests comments under the comments they reply to. Replies that
+// would make a loop, or whose thread position is not below their parent's, are
+// kept at the top level
+func BuildCommentTree(comments []Comment) []*CommentNode {
+	nodes := make(map[int]*CommentNode, len(comments))
+	for _, c := range comments {
+		nodes[c.ID] = &CommentNode{Comment: c}
+	}
+	parentOf := map[int]int{}
+	var roots []*CommentNode
+	for _, c := range comments {
+		node := nodes[c.ID]
+		parent, ok := nodes[c.ReplyID]
+		if c.ReplyID == 0 || !ok || !canAttach(parentOf, node, parent) {
+			roots = append(roots, node)
+			continue
+		}
+		parentOf[c.ID] = c.ReplyID
+		parent.Children = append(parent.Children, node)
+	}
+	sortCommentNodes(roots)
+	return roots
+}
+
+// canAttach reports whether child can be nested under parent without making a loop
+func canAttach(parentOf map[int]int, child, parent *CommentNode) bool {
+	cp, pp := child.Comment.ThreadPosition, parent.Comment.ThreadPosition
+	if cp != "" && pp != "" && !strings.HasPrefix(cp, pp+".") {
+		return false
+	}
+	for id := parent.Comment.ID; ; {
+		if id == child.Comment.ID {
+			return false
+		}
+		next, ok := parentOf[id]
+		if !ok {
+			return true
+		}
+		id = next
+	}
+}
+
+func sortCommentNodes(nodes []*CommentNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return lessThreadPosition(nodes[i].Comment.ThreadPosition, nodes[j].Comment.ThreadPosition)
+	})
+	for _, n := range nodes {
+		sortCommentNodes(n.Children)
+	}
+}
+
+// lessThreadPosition compares thread positions such as "01.02" segment by segment
+func lessThreadPosition(a, b string) bool {
+	as := strings.Split(a, ".")
+	bs := strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, errX := strconv.Atoi(as[i])
+		y, errY := strconv.Atoi(bs[i])
+		if errX != nil || errY != nil {
+			if as[i] != bs[i] {
+				return as[i] < bs[i]
+			}
+			continue
+		}
+		if x != y {
+			return x < y
+		}
+	}
+	return len(as) < len(bs)
+}
+
+// ReplyToComment adds a comment replying to parentID. An error is returned when
+// the reply would be nested deeper than mod.io allows
+func (user *User) ReplyToComment(parentID int, content string, modID, gameID int) (*Comment, error) {
+	if parentID <= 0 {
+		return nil, errors.New("parent comment ID is required")
+	}
+	parent, err := user.GetModComment(parentID, modID, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if parent.Depth() >= maxCommentDepth {
+		return nil, fmt.Errorf("comment %d is at the maximum reply depth of %d", parentID, maxCommentDepth)
+	}
+	return user.AddModComment(content, modID, gameID, &AddModCommentOptions{ReplyID: &parentID})
+}
@@ -0,0 +1,84 @@
+package gomodio
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLessThreadPosition(t *testing.T) {
+	tests := []struct {
+		a, b string
+		less bool
+	}{
+		{"01", "02", true},
+		{"02", "01", false},
+		{"01.02", "01.10", true},
+		{"01.10", "01.02", false},
+		{"01", "01.01", true},
+		{"01.01", "01", false},
+		{"09", "10", true},
+		{"01.01.01", "01.02", true},
+	}
+	for _, tt := range tests {
+		if got := lessThreadPosition(tt.a, tt.b); got != tt.less {
+			t.Errorf("lessThreadPosition(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.less)
+		}
+	}
+}
+
+// treeIDs flattens a tree as "id(child child)" strings for comparison
+func treeIDs(nodes []*CommentNode) []interface{} {
+	var out []interface{}
+	for _, n := range nodes {
+		if len(n.Children) == 0 {
+			out = append(out, n.Comment.ID)
+			continue
+		}
+		out = append(out, []interface{}{n.Comment.ID, treeIDs(n.Children)})
+	}
+	return out
+}
+
+func TestBuildCommentTree(t *testing.T) {
+	comments := []Comment{
+		{ID: 4, ReplyID: 1, ThreadPosition: "01.10"},
+		{ID: 1, ThreadPosition: "01"},
+		{ID: 3, ReplyID: 1, ThreadPosition: "01.02"},
+		{ID: 5, ReplyID: 3, ThreadPosition: "01.02.01"},
+		{ID: 2, ThreadPosition: "02"},
+		// reply whose parent was deleted
+		{ID: 6, ReplyID: 99, ThreadPosition: "03.01"},
+	}
+	got := treeIDs(BuildCommentTree(comments))
+	want := []interface{}{
+		[]interface{}{1, []interface{}{[]interface{}{3, []interface{}{5}}, 4}},
+		2,
+		6,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tree = %v, want %v", got, want)
+	}
+}
+
+func TestBuildCommentTreeCycle(t *testing.T) {
+	tests := [][]Comment{
+		{{ID: 1, ReplyID: 2, ThreadPosition: "01.01"}, {ID: 2, ReplyID: 1, ThreadPosition: "01.01"}},
+		{{ID: 1, ReplyID: 2}, {ID: 2, ReplyID: 1}},
+		{{ID: 1, ReplyID: 1}},
+	}
+	for _, comments := range tests {
+		roots := BuildCommentTree(comments)
+		count := 0
+		var walk func([]*CommentNode)
+		walk = func(nodes []*CommentNode) {
+			for _, n := range nodes {
+				count++
+				walk(n.Children)
+			}
+		}
+		walk(roots)
+		if count != len(comments) {
+			t.Errorf("tree of %v has %d nodes, want %d", comments, count, len(comments))
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package gomodio
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Status of a mod or game
+type Status int
+
+// Status values
+const (
+	StatusNotAccepted Status = 0
+	StatusAccepted    Status = 1
+	StatusDeleted     Status = 3
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusNotAccepted:
+		return "not accepted"
+	case StatusAccepted:
+		return "accepted"
+	case StatusDeleted:
+		return "deleted"
+	}
+	return "status(" + strconv.Itoa(int(s)) + ")"
+}
+
+// Visibility of a mod
+type Visibility int
+
+// Visibility values
+const (
+	VisibilityHidden Visibility = 0
+	VisibilityPublic Visibility = 1
+)
+
+func (v Visibility) String() string {
+	switch v {
+	case VisibilityHidden:
+		return "hidden"
+	case VisibilityPublic:
+		return "public"
+	}
+	return "visibility(" + strconv.Itoa(int(v)) + ")"
+}
+
+// VirusStatus is the state of a modfile's virus scan
+type VirusStatus int
+
+// VirusStatus values
+const (
+	VirusNotScanned VirusStatus = 0
+	VirusScanned    VirusStatus = 1
+	VirusInProgress VirusStatus = 2
+	VirusTooLarge   VirusStatus = 3
+	VirusNotFound   VirusStatus = 4
+	VirusError      VirusStatus = 5
+)
+
+func (v VirusStatus) String() string {
+	switch v {
+	case VirusNotScanned:
+		return "not scanned"
+	case VirusScanned:
+		return "scanned"
+	case VirusInProgress:
+		return "in progress"
+	case VirusTooLarge:
+		return "too large to scan"
+	case VirusNotFound:
+		return "file not found"
+	case VirusError:
+		return "error scanning"
+	}
+	return "virus status(" + strconv.Itoa(int(v)) + ")"
+}
+
+// VirusPositive is the result of a modfile's virus scan
+type VirusPositive int
+
+// VirusPositive values
+const (
+	VirusNoThreats          VirusPositive = 0
+	VirusMalicious          VirusPositive = 1
+	VirusPotentiallyHarmful VirusPositive = 2
+)
+
+func (v VirusPositive) String() string {
+	switch v {
+	case VirusNoThreats:
+		return "no threats detected"
+	case VirusMalicious:
+		return "malicious"
+	case VirusPotentiallyHarmful:
+		return "potentially harmful"
+	}
+	return "virus positive(" + strconv.Itoa(int(v)) + ")"
+}
+
+// MaturityOption is the bitwise set of mature content a mod contains
+type MaturityOption int
+
+// MaturityOption flags
+const (
+	MaturityNone     MaturityOption = 0
+	MaturityAlcohol  MaturityOption = 1
+	MaturityDrugs    MaturityOption = 2
+	MaturityViolence MaturityOption = 4
+	MaturityExplicit MaturityOption = 8
+)
+
+// Has reports whether all bits of flag are set
+func (m MaturityOption) Has(flag MaturityOption) bool {
+	return m&flag == flag
+}
+
+func (m MaturityOption) String() string {
+	return flagString(int(m), []string{"alcohol", "drugs", "violence", "explicit"})
+}
+
+// GameMaturityOption is whether a game allows mods with mature content
+type GameMaturityOption int
+
+// GameMaturityOption values
+const (
+	GameMaturityDisallowed GameMaturityOption = 0
+	GameMaturityAllowed    GameMaturityOption = 1
+	GameMaturityOnly       GameMaturityOption = 2
+)
+
+func (g GameMaturityOption) String() string {
+	switch g {
+	case GameMaturityDisallowed:
+		return "mature content not allowed"
+	case GameMaturityAllowed:
+		return "mature content allowed"
+	case GameMaturityOnly:
+		return "mature audiences only"
+	}
+	return "maturity options(" + strconv.Itoa(int(g)) + ")"
+}
+
+// CommunityOptions is the bitwise set of community features a game enables
+type CommunityOptions int
+
+// CommunityOptions flags
+const (
+	CommunityNone               CommunityOptions = 0
+	CommunityComments           CommunityOptions = 1
+	CommunityGuides             CommunityOptions = 2
+	CommunityPinOnHomepage      CommunityOptions = 4
+	CommunityShowOnHomepage     CommunityOptions = 8
+	CommunityShowMoreOnHomepage CommunityOptions = 16
+	CommunityAllowChangeStatus  CommunityOptions = 32
+	CommunityPreviews           CommunityOptions = 64
+	CommunityPreviewShareURL    CommunityOptions = 128
+	CommunityNegativeRatings    CommunityOptions = 256
+	CommunityWebEdit            CommunityOptions = 512
+	CommunityDependencies       CommunityOptions = 1024
+)
+
+// Has reports whether all bits of flag are set
+func (c CommunityOptions) Has(flag CommunityOptions) bool {
+	return c&flag == flag
+}
+
+func (c CommunityOptions) String() string {
+	return flagString(int(c), []string{
+		"comments", "guides", "pin on homepage", "show on homepage", "show more on homepage",
+		"allow change status", "previews", "preview share url", "negative ratings", "web edit", "dependencies",
+	})
+}
+
+// APIAccessOptions is the bitwise set of third party API access a game allows
+type APIAccessOptions int
+
+// APIAccessOptions flags
+const (
+	APIAccessNone            APIAccessOptions = 0
+	APIAccessThirdParty      APIAccessOptions = 1
+	APIAccessDirectDownloads APIAccessOptions = 2
+)
+
+// Has reports whether all bits of flag are set
+func (a APIAccessOptions) Has(flag APIAccessOptions) bool {
+	return a&flag == flag
+}
+
+func (a APIAccessOptions) String() string {
+	return flagString(int(a), []string{"third party access", "direct downloads"})
+}
+
+// flagString names each set bit of v, in bit order, joined by "|"
+func flagString(v int, names []string) string {
+	if v == 0 {
+		return "none"
+	}
+	var set []string
+	for i, name := range names {
+		if v&(1<<uint(i)) != 0 {
+			set = append(set, name)
+			v &^= 1 << uint(i)
+		}
+	}
+	if v != 0 {
+		set = append(set, strconv.Itoa(v))
+	}
+	return strings.Join(set, "|")
+}
@@ -0,0 +1,78 @@
+package gomodio
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestEnumStrings(t *testing.T) {
+	tests := []struct {
+		value fmt.Stringer
+		want  string
+	}{
+		{StatusNotAccepted, "not accepted"},
+		{StatusAccepted, "accepted"},
+		{StatusDeleted, "deleted"},
+		{Status(2), "status(2)"},
+		{VisibilityHidden, "hidden"},
+		{VisibilityPublic, "public"},
+		{Visibility(7), "visibility(7)"},
+		{VirusInProgress, "in progress"},
+		{VirusError, "error scanning"},
+		{VirusStatus(9), "virus status(9)"},
+		{VirusNoThreats, "no threats detected"},
+		{VirusMalicious, "malicious"},
+		{VirusPositive(3), "virus positive(3)"},
+		{GameMaturityOnly, "mature audiences only"},
+		{GameMaturityOption(5), "maturity options(5)"},
+		{MaturityNone, "none"},
+		{MaturityAlcohol | MaturityViolence, "alcohol|violence"},
+		{MaturityOption(16 | 2), "drugs|16"},
+		{CommunityComments | CommunityDependencies, "comments|dependencies"},
+		{APIAccessThirdParty | APIAccessDirectDownloads, "third party access|direct downloads"},
+	}
+	for _, tt := range tests {
+		if got := tt.value.String(); got != tt.want {
+			t.Errorf("%T(%v).String() = %q, want %q", tt.value, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestEnumFlags(t *testing.T) {
+	m := MaturityDrugs | MaturityExplicit
+	if !m.Has(MaturityDrugs) || !m.Has(MaturityDrugs|MaturityExplicit) || m.Has(MaturityAlcohol) {
+		t.Errorf("MaturityOption(%d).Has gave wrong results", m)
+	}
+	c := CommunityComments | CommunityNegativeRatings
+	if !c.Has(CommunityNegativeRatings) || c.Has(CommunityGuides) || c.Has(CommunityComments|CommunityGuides) {
+		t.Errorf("CommunityOptions(%d).Has gave wrong results", c)
+	}
+	a := APIAccessDirectDownloads
+	if !a.Has(APIAccessDirectDownloads) || a.Has(APIAccessThirdParty) {
+		t.Errorf("APIAccessOptions(%d).Has gave wrong results", a)
+	}
+}
+
+func TestEnumDecode(t *testing.T) {
+	var mod Mod
+	err := json.Unmarshal([]byte(`{"status":1,"visible":0,"maturity_option":5,"modfile":{"virus_status":1,"virus_positive":2}}`), &mod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mod.Status != StatusAccepted || mod.Visible != VisibilityHidden || mod.MaturityOption != MaturityAlcohol|MaturityViolence {
+		t.Errorf("mod = status %v, visible %v, maturity %v", mod.Status, mod.Visible, mod.MaturityOption)
+	}
+	if mod.Modfile.VirusStatus != VirusScanned || mod.Modfile.VirusPositive != VirusPotentiallyHarmful {
+		t.Errorf("modfile = %v, %v", mod.Modfile.VirusStatus, mod.Modfile.VirusPositive)
+	}
+	var game Game
+	err = json.Unmarshal([]byte(`{"status":3,"community_options":3,"api_access_options":1,"maturity_options":2}`), &game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if game.Status != StatusDeleted || game.CommunityOptions != CommunityComments|CommunityGuides ||
+		game.APIAccessOptions != APIAccessThirdParty || game.MaturityOptions != GameMaturityOnly {
+		t.Errorf("game = %+v", game)
+	}
+}
@@ -20,11 +20,11 @@ type Events struct {
 
 // Event struct represents the event object of mod.io's API
 type Event struct {
-	ID        int    `json:"id"`
-	ModID     int    `json:"mod_id"`
-	UserID    int    `json:"user_id"`
-	DateAdded int    `json:"date_added"`
-	EventType string `json:"event_type"`
+	ID        int       `json:"id"`
+	ModID     int       `json:"mod_id"`
+	UserID    int       `json:"user_id"`
+	DateAdded Timestamp `json:"date_added"`
+	EventType string    `json:"event_type"`
 }
 
 // GetModsEvents gets all mods events
@@ -37,15 +37,11 @@ func (user *User) GetModsEvents(gameID int, options map[string]string) (e *Event
 		queryStr = "api_key=" + user.APIKey()
 	}
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/events?"+queryStr, nil)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/events?"+queryStr, nil)
 	if err != nil {
 		return nil, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return nil, err
 	}
@@ -72,15 +68,11 @@ func (user *User) GetModsEvents(gameID int, options map[string]string) (e *Event
 // GetModEvents gets a single mod's events
 func (user *User) GetModEvents(gameID int, modID int) (e *Events, err error) {
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/events", nil)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/events", nil)
 	if err != nil {
 		return nil, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return nil, err
 	}
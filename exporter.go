@@ -0,0 +1,184 @@
+package gomodio
+
+import (
+	"bufio"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// StatsExporter is an http.Handler serving game and mod stats as Prometheus
+// text-format gauges. Stats are cached until the earliest DateExpires mod.io sent
+type StatsExporter struct {
+	user    *User
+	gameIDs []int
+	// MinRefresh is the shortest time stats are cached for, used when mod.io sends
+	// no expiry. Defaults to one minute
+	MinRefresh time.Duration
+
+	mu    sync.Mutex
+	cache map[int]*exportedStats
+}
+
+type exportedStats struct {
+	game    *GameStats
+	mods    []Stats
+	expires time.Time
+	up      bool
+	// refreshing is set while a scrape fetches the game's stats, so concurrent
+	// scrapes serve the last stats instead of fetching them again
+	refreshing bool
+}
+
+// NewStatsExporter returns an exporter of the stats of the given games
+func NewStatsExporter(user *User, gameIDs ...int) *StatsExporter {
+	return &StatsExporter{
+		user:       user,
+		gameIDs:    gameIDs,
+		MinRefresh: time.Minute,
+		cache:      map[int]*exportedStats{},
+	}
+}
+
+type gameGauge struct {
+	name  string
+	help  string
+	value func(g *GameStats) float64
+}
+
+type modGauge struct {
+	name  string
+	help  string
+	value func(s *Stats) float64
+}
+
+var gameGauges = []gameGauge{
+	{"modio_game_mods_count_total", "Number of live mods of the game", func(g *GameStats) float64 { return float64(g.ModsCountTotal) }},
+	{"modio_game_mods_downloads_today", "Downloads of the game's mods in the last 24 hours", func(g *GameStats) float64 { return float64(g.ModsDownloadsToday) }},
+	{"modio_game_mods_downloads_total", "Downloads of the game's mods", func(g *GameStats) float64 { return float64(g.ModsDownloadsTotal) }},
+	{"modio_game_mods_downloads_daily_average", "Average daily downloads of the game's mods", func(g *GameStats) float64 { return float64(g.ModsDownloadsDailyAverage) }},
+	{"modio_game_mods_subscribers_total", "Subscribers of the game's mods", func(g *GameStats) float64 { return float64(g.ModsSubscribersTotal) }},
+}
+
+var modGauges = []modGauge{
+	{"modio_mod_downloads_total", "Downloads of the mod", func(s *Stats) float64 { return float64(s.DownloadsTotal) }},
+	{"modio_mod_subscribers_total", "Subscribers of the mod", func(s *Stats) float64 { return float64(s.SubscribersTotal) }},
+	{"modio_mod_ratings_total", "Ratings of the mod", func(s *Stats) float64 { return float64(s.RatingsTotal) }},
+	{"modio_mod_ratings_positive", "Positive ratings of the mod", func(s *Stats) float64 { return float64(s.RatingsPositive) }},
+	{"modio_mod_ratings_negative", "Negative ratings of the mod", func(s *Stats) float64 { return float64(s.RatingsNegative) }},
+	{"modio_mod_ratings_weighted_aggregate", "Weighted rating of the mod between 0 and 1", func(s *Stats) float64 { return s.RatingsWeightedAggregate }},
+	{"modio_mod_popularity_rank_position", "Popularity rank of the mod, 1 being the most popular", func(s *Stats) float64 { return float64(s.PopularityRankPosition) }},
+}
+
+// ServeHTTP writes the metrics, refreshing expired stats first. When a refresh fails
+// the last stats are served and modio_up reports 0 for the game
+func (e *StatsExporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for _, id := range e.gameIDs {
+		e.refresh(id)
+	}
+	// Refreshes replace the game and mods rather than modifying them, so a copy
+	// of each entry can be written out without holding the lock
+	stats := map[int]exportedStats{}
+	e.mu.Lock()
+	for _, id := range e.gameIDs {
+		stats[id] = *e.cache[id]
+	}
+	e.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+
+	writeHeader(bw, "modio_up", "Whether the last refresh of the game's stats succeeded")
+	for _, id := range e.gameIDs {
+		v := 0.0
+		if stats[id].up {
+			v = 1
+		}
+		writeSample(bw, "modio_up", gameLabels(id), v)
+	}
+	for _, g := range gameGauges {
+		writeHeader(bw, g.name, g.help)
+		for _, id := range e.gameIDs {
+			if s := stats[id].game; s != nil {
+				writeSample(bw, g.name, gameLabels(id), g.value(s))
+			}
+		}
+	}
+	for _, g := range modGauges {
+		writeHeader(bw, g.name, g.help)
+		for _, id := range e.gameIDs {
+			mods := stats[id].mods
+			for i := range mods {
+				writeSample(bw, g.name, gameLabels(id)+`,mod_id="`+strconv.Itoa(mods[i].ModID)+`"`, g.value(&mods[i]))
+			}
+		}
+	}
+}
+
+// refresh fetches a game's stats when its cached stats have expired. The lock is
+// not held while fetching
+func (e *StatsExporter) refresh(gameID int) {
+	e.mu.Lock()
+	c, ok := e.cache[gameID]
+	if !ok {
+		c = &exportedStats{}
+		e.cache[gameID] = c
+	}
+	if c.refreshing || time.Now().Before(c.expires) {
+		e.mu.Unlock()
+		return
+	}
+	c.refreshing = true
+	e.mu.Unlock()
+
+	game, err := e.user.GetGameStats(gameID)
+	var mods []Stats
+	if err == nil {
+		mods, err = e.user.GetAllModsStats(gameID, nil)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c.refreshing = false
+	now := time.Now()
+	if err != nil {
+		c.up = false
+		c.expires = now.Add(e.MinRefresh)
+		return
+	}
+	c.game, c.mods, c.up = game, mods, true
+	c.expires = statsExpiry(game, mods, now.Add(e.MinRefresh))
+}
+
+// statsExpiry returns the earliest date_expires of a game's and its mods' stats,
+// but no earlier than min. min is returned when no expiry was sent
+func statsExpiry(game *GameStats, mods []Stats, min time.Time) time.Time {
+	var expires Timestamp
+	if game != nil {
+		expires = game.DateExpires
+	}
+	for _, m := range mods {
+		if !m.DateExpires.IsZero() && (expires.IsZero() || m.DateExpires < expires) {
+			expires = m.DateExpires
+		}
+	}
+	if expires.IsZero() || expires.Time().Before(min) {
+		return min
+	}
+	return expires.Time()
+}
+
+func gameLabels(gameID int) string {
+	return `game_id="` + strconv.Itoa(gameID) + `"`
+}
+
+func writeHeader(w *bufio.Writer, name, help string) {
+	w.WriteString("# HELP " + name + " " + help + "\n")
+	w.WriteString("# TYPE " + name + " gauge\n")
+}
+
+func writeSample(w *bufio.Writer, name, labels string, v float64) {
+	w.WriteString(name + "{" + labels + "} " + strconv.FormatFloat(v, 'g', -1, 64) + "\n")
+}
@@ -0,0 +1,110 @@
+package gomodio
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStatsExpiry(t *testing.T) {
+	now := time.Now()
+	min := now.Add(time.Minute)
+	in := func(d time.Duration) Timestamp { return NewTimestamp(now.Add(d)) }
+	tests := []struct {
+		name string
+		game Timestamp
+		mods []Timestamp
+		want time.Time
+	}{
+		{"no expiry", 0, []Timestamp{0, 0}, min},
+		{"game only", in(time.Hour), nil, in(time.Hour).Time()},
+		{"mods without game", 0, []Timestamp{in(2 * time.Hour), in(time.Hour), 0}, in(time.Hour).Time()},
+		{"earliest of game and mods", in(3 * time.Hour), []Timestamp{in(2 * time.Hour)}, in(2 * time.Hour).Time()},
+		{"clamped to min", in(time.Hour), []Timestamp{in(time.Second)}, min},
+	}
+	for _, tt := range tests {
+		var mods []Stats
+		for _, e := range tt.mods {
+			mods = append(mods, Stats{DateExpires: e})
+		}
+		if got := statsExpiry(&GameStats{DateExpires: tt.game}, mods, min); !got.Equal(tt.want) {
+			t.Errorf("%s: expiry = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStatsExporterScrape(t *testing.T) {
+	var requests int32
+	modExpires := time.Now().Add(time.Hour).Unix()
+	user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if strings.HasSuffix(r.URL.Path, "/mods/stats") {
+			w.Write([]byte(`{"data":[{"mod_id":2,"downloads_total":1500,"ratings_weighted_aggregate":0.75,"date_expires":` +
+				strconv.FormatInt(modExpires, 10) + `}],"result_total":1}`))
+			return
+		}
+		w.Write([]byte(`{"game_id":1,"mods_count_total":12,"mods_downloads_total":3000}`))
+	})
+	e := NewStatsExporter(user, 1)
+	scrape := func() string {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+		if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		return rec.Body.String()
+	}
+
+	body := scrape()
+	for _, want := range []string{
+		"# TYPE modio_up gauge\n",
+		`modio_up{game_id="1"} 1` + "\n",
+		`modio_game_mods_count_total{game_id="1"} 12` + "\n",
+		`modio_game_mods_downloads_total{game_id="1"} 3000` + "\n",
+		`modio_mod_downloads_total{game_id="1",mod_id="2"} 1500` + "\n",
+		`modio_mod_ratings_weighted_aggregate{game_id="1",mod_id="2"} 0.75` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("first scrape sent %d requests, want 2", requests)
+	}
+
+	// The game sent no expiry, so the mod's date_expires decides the refresh
+	if got := e.cache[1].expires; got.Unix() != modExpires {
+		t.Errorf("expires = %v, want the mod's %v", got, time.Unix(modExpires, 0))
+	}
+	scrape()
+	if requests != 2 {
+		t.Errorf("scrape before expiry sent %d more requests", requests-2)
+	}
+	e.cache[1].expires = time.Now().Add(-time.Second)
+	scrape()
+	if requests != 4 {
+		t.Errorf("scrape after expiry sent %d requests, want 2 more", requests-2)
+	}
+}
+
+func TestStatsExporterDown(t *testing.T) {
+	user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":{"code":503,"error_ref":10000,"message":"outage"}}`))
+	})
+	e := NewStatsExporter(user, 1)
+	e.MinRefresh = time.Hour
+	rec := httptest.NewRecorder()
+	start := time.Now()
+	e.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), `modio_up{game_id="1"} 0`) {
+		t.Errorf("metrics do not report the game down:\n%s", rec.Body.String())
+	}
+	if got := e.cache[1].expires; got.Before(start.Add(time.Hour)) {
+		t.Errorf("failed refresh retried at %v, want after MinRefresh", got)
+	}
+}
@@ -0,0 +1,167 @@
+package gomodio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrTermsNotAccepted is returned by third-party authentication when the
+// player has not agreed to mod.io's terms of use
+var ErrTermsNotAccepted = errors.New("terms of use not accepted")
+
+// ErrPlatformNotEnabled is returned by third-party authentication when the
+// game has not enabled authentication through that platform
+var ErrPlatformNotEnabled = errors.New("platform authentication not enabled")
+
+// errorRefTermsNotAccepted is the error_ref mod.io sends when terms_agreed is missing
+const errorRefTermsNotAccepted = 11074
+
+// errorRefsPlatformNotEnabled are the error_ref values mod.io sends when the game has
+// not configured a platform's credentials, such as its Steam or GOG Galaxy app ticket,
+// mapped to the platform they belong to
+var errorRefsPlatformNotEnabled = map[int]string{
+	11019: "steam",
+	11021: "gog",
+	11024: "oculus rift",
+	11025: "oculus quest",
+	11027: "switch",
+	11029: "xbox",
+	11031: "itch.io",
+	11042: "discord",
+	11046: "google",
+	11063: "epic games",
+	11079: "psn",
+	11086: "openid",
+}
+
+// ExternalAuthOptions are the fields shared by all third-party authentication calls
+type ExternalAuthOptions struct {
+	// Email optionally links the player's mod.io account to this address
+	Email string
+	// TermsAgreed must be true once the player has accepted mod.io's terms of use
+	TermsAgreed bool
+	// Lifetime of the token. Zero uses mod.io's default of one year
+	Lifetime time.Duration
+}
+
+// SteamAuth authenticates with a base64 encoded Steam encrypted app ticket
+func (u *User) SteamAuth(ctx context.Context, appdata string, opts ExternalAuthOptions) (*User, error) {
+	return u.externalAuth(ctx, "steamauth", url.Values{"appdata": {appdata}}, opts)
+}
+
+// XboxAuth authenticates with an Xbox Live token
+func (u *User) XboxAuth(ctx context.Context, xboxToken string, opts ExternalAuthOptions) (*User, error) {
+	return u.externalAuth(ctx, "xboxauth", url.Values{"xbox_token": {xboxToken}}, opts)
+}
+
+// PSNAuth authenticates with a PlayStation Network auth code
+func (u *User) PSNAuth(ctx context.Context, authCode string, opts ExternalAuthOptions) (*User, error) {
+	return u.externalAuth(ctx, "psnauth", url.Values{"auth_code": {authCode}}, opts)
+}
+
+// SwitchAuth authenticates with a Nintendo Switch NSA ID token
+func (u *User) SwitchAuth(ctx context.Context, idToken string, opts ExternalAuthOptions) (*User, error) {
+	return u.externalAuth(ctx, "switchauth", url.Values{"id_token": {idToken}}, opts)
+}
+
+// EpicGamesAuth authenticates with an Epic Online Services access token
+func (u *User) EpicGamesAuth(ctx context.Context, accessToken string, opts ExternalAuthOptions) (*User, error) {
+	return u.externalAuth(ctx, "epicgamesauth", url.Values{"access_token": {accessToken}}, opts)
+}
+
+// GOGAuth authenticates with a base64 encoded GOG Galaxy encrypted app ticket
+func (u *User) GOGAuth(ctx context.Context, appdata string, opts ExternalAuthOptions) (*User, error) {
+	return u.externalAuth(ctx, "galaxyauth", url.Values{"appdata": {appdata}}, opts)
+}
+
+// ItchioAuth authenticates with an itch.io JWT token
+func (u *User) ItchioAuth(ctx context.Context, itchioToken string, opts ExternalAuthOptions) (*User, error) {
+	return u.externalAuth(ctx, "itchioauth", url.Values{"itchio_token": {itchioToken}}, opts)
+}
+
+// OculusAuth authenticates with an Oculus user proof. device is "rift" or "quest"
+func (u *User) OculusAuth(ctx context.Context, device, nonce string, userID int, authToken string, opts ExternalAuthOptions) (*User, error) {
+	form := url.Values{
+		"device":     {device},
+		"nonce":      {nonce},
+		"user_id":    {strconv.Itoa(userID)},
+		"auth_token": {authToken},
+	}
+	return u.externalAuth(ctx, "oculusauth", form, opts)
+}
+
+// GoogleAuth authenticates with a Google ID token
+func (u *User) GoogleAuth(ctx context.Context, idToken string, opts ExternalAuthOptions) (*User, error) {
+	return u.externalAuth(ctx, "googleauth", url.Values{"id_token": {idToken}}, opts)
+}
+
+// DiscordAuth authenticates with a Discord access token
+func (u *User) DiscordAuth(ctx context.Context, discordToken string, opts ExternalAuthOptions) (*User, error) {
+	return u.externalAuth(ctx, "discordauth", url.Values{"discord_token": {discordToken}}, opts)
+}
+
+// OpenIDAuth authenticates with an ID token from the game's OpenID provider
+func (u *User) OpenIDAuth(ctx context.Context, idToken string, opts ExternalAuthOptions) (*User, error) {
+	return u.externalAuth(ctx, "openidauth", url.Values{"id_token": {idToken}}, opts)
+}
+
+func (u *User) externalAuth(ctx context.Context, endpoint string, form url.Values, opts ExternalAuthOptions) (*User, error) {
+	form.Set("api_key", u.APIKey())
+	if opts.Email != "" {
+		form.Set("email", opts.Email)
+	}
+	if opts.TermsAgreed {
+		form.Set("terms_agreed", "true")
+	}
+	if opts.Lifetime > 0 {
+		form.Set("date_expires", strconv.FormatInt(time.Now().Add(opts.Lifetime).Unix(), 10))
+	}
+	client := http.Client{Timeout: time.Duration(5 * time.Second)}
+	req, err := u.newRequestWithContext(ctx, "POST", "https://api.mod.io/v1/external/"+endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := u.do(&client, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		var errObj ErrorCase
+		e := json.Unmarshal(body, &errObj)
+		if e != nil {
+			return nil, e
+		}
+		if errObj.Error.Code == errorRefTermsNotAccepted {
+			return nil, fmt.Errorf("%w: %v", ErrTermsNotAccepted, HandleResponseError(errObj))
+		}
+		if _, ok := errorRefsPlatformNotEnabled[errObj.Error.Code]; ok {
+			return nil, fmt.Errorf("%w: %v", ErrPlatformNotEnabled, HandleResponseError(errObj))
+		}
+		return nil, HandleResponseError(errObj)
+	}
+	var res ExchangeResponse
+	err = json.Unmarshal(body, &res)
+	if err != nil {
+		return nil, err
+	}
+	u.SetOAuth2Token(res.OAuthToken)
+	u.tokenExpires = res.DateExpires
+	err = u.saveToken()
+	if err != nil {
+		return u, err
+	}
+	return u, nil
+}
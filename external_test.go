@@ -0,0 +1,78 @@
+package gomodio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestExternalAuthPlatformNotEnabled(t *testing.T) {
+	opts := ExternalAuthOptions{TermsAgreed: true}
+	tests := []struct {
+		name     string
+		endpoint string
+		ref      int
+		auth     func(u *User) (*User, error)
+	}{
+		{"steam", "steamauth", 11019, func(u *User) (*User, error) { return u.SteamAuth(context.Background(), "ticket", opts) }},
+		{"gog", "galaxyauth", 11021, func(u *User) (*User, error) { return u.GOGAuth(context.Background(), "ticket", opts) }},
+		{"oculus rift", "oculusauth", 11024, func(u *User) (*User, error) {
+			return u.OculusAuth(context.Background(), "rift", "nonce", 1, "token", opts)
+		}},
+		{"oculus quest", "oculusauth", 11025, func(u *User) (*User, error) {
+			return u.OculusAuth(context.Background(), "quest", "nonce", 1, "token", opts)
+		}},
+		{"switch", "switchauth", 11027, func(u *User) (*User, error) { return u.SwitchAuth(context.Background(), "token", opts) }},
+		{"xbox", "xboxauth", 11029, func(u *User) (*User, error) { return u.XboxAuth(context.Background(), "token", opts) }},
+		{"itch.io", "itchioauth", 11031, func(u *User) (*User, error) { return u.ItchioAuth(context.Background(), "token", opts) }},
+		{"discord", "discordauth", 11042, func(u *User) (*User, error) { return u.DiscordAuth(context.Background(), "token", opts) }},
+		{"google", "googleauth", 11046, func(u *User) (*User, error) { return u.GoogleAuth(context.Background(), "token", opts) }},
+		{"epic games", "epicgamesauth", 11063, func(u *User) (*User, error) {
+			return u.EpicGamesAuth(context.Background(), "token", opts)
+		}},
+		{"psn", "psnauth", 11079, func(u *User) (*User, error) { return u.PSNAuth(context.Background(), "code", opts) }},
+		{"openid", "openidauth", 11086, func(u *User) (*User, error) { return u.OpenIDAuth(context.Background(), "token", opts) }},
+	}
+	for _, tt := range tests {
+		user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v1/external/"+tt.endpoint {
+				t.Errorf("%s: path = %s", tt.name, r.URL.Path)
+			}
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":{"code":403,"error_ref":` + strconv.Itoa(tt.ref) + `,"message":"not configured"}}`))
+		})
+		_, err := tt.auth(user)
+		if !errors.Is(err, ErrPlatformNotEnabled) {
+			t.Errorf("%s: error = %v, want ErrPlatformNotEnabled", tt.name, err)
+		}
+	}
+}
+
+func TestExternalAuthErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want error
+	}{
+		{"terms", `{"error":{"code":403,"error_ref":11074,"message":"terms not agreed"}}`, ErrTermsNotAccepted},
+		{"other 403", `{"error":{"code":403,"error_ref":11000,"message":"forbidden"}}`, nil},
+	}
+	for _, tt := range tests {
+		user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(tt.body))
+		})
+		_, err := user.SteamAuth(context.Background(), "ticket", ExternalAuthOptions{})
+		if err == nil {
+			t.Fatalf("%s: no error", tt.name)
+		}
+		if tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.want)
+		}
+		if tt.want == nil && (errors.Is(err, ErrPlatformNotEnabled) || errors.Is(err, ErrTermsNotAccepted)) {
+			t.Errorf("%s: error = %v, want an untyped error", tt.name, err)
+		}
+	}
+}
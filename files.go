@@ -4,13 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
-	"io"
 	"io/ioutil"
-	"log"
 	"mime/multipart"
 	"net/http"
-	"os"
-	"path/filepath"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -27,51 +24,59 @@ type Modfiles struct {
 
 // File struct which maps to the JSON of Get/Add/Delete File
 type File struct {
-	ID             int    `json:"id"`
-	ModID          int    `json:"mod_id"`
-	DateAdded      int    `json:"date_added"`
-	DateScanned    int    `json:"date_scanned"`
-	VirusStatus    int    `json:"virus_status"`
-	VirusPositive  int    `json:"virus_positive"`
-	VirustotalHash string `json:"virustotal_hash"`
-	Filesize       int    `json:"filesize"`
-	Filehash       struct {
-		Md5 string `json:"md5"`
-	} `json:"filehash"`
-	Filename     string `json:"filename"`
-	Version      string `json:"version"`
-	Changelog    string `json:"changelog"`
-	MetadataBlob string `json:"metadata_blob"`
-	Download     struct {
-		BinaryURL   string `json:"binary_url"`
-		DateExpires int    `json:"date_expires"`
-	} `json:"download"`
+	ID             int            `json:"id"`
+	ModID          int            `json:"mod_id"`
+	DateAdded      Timestamp      `json:"date_added"`
+	DateScanned    Timestamp      `json:"date_scanned"`
+	VirusStatus    VirusStatus    `json:"virus_status"`
+	VirusPositive  VirusPositive  `json:"virus_positive"`
+	VirustotalHash string         `json:"virustotal_hash"`
+	Filesize       int            `json:"filesize"`
+	Filehash       Filehash       `json:"filehash"`
+	Filename       string         `json:"filename"`
+	Version        string         `json:"version"`
+	Changelog      string         `json:"changelog"`
+	MetadataBlob   string         `json:"metadata_blob"`
+	Download       Download       `json:"download"`
+	Platforms      []FilePlatform `json:"platforms"`
+}
+
+// Filehash struct represents the hashes of a modfile
+type Filehash struct {
+	Md5 string `json:"md5"`
+}
+
+// Download struct represents a modfile's download link and when it expires
+type Download struct {
+	BinaryURL   string    `json:"binary_url"`
+	DateExpires Timestamp `json:"date_expires"`
 }
 
 // GetModfiles grabs modfiles and returns a Modfiles struct
 func GetModfiles(modID int, gameID int, options map[string]string, user *User) (f *Modfiles, err error) {
-	options["api_key"] = user.APIKey()
-	queryStr := ParseArgsGet(options)
+	var queryStr string
+	if options != nil {
+		options["api_key"] = user.APIKey()
+		queryStr = ParseArgsGet(options)
+	} else {
+		queryStr = "api_key=" + user.APIKey()
+	}
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"?"+queryStr, nil)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/files?"+queryStr, nil)
 	if err != nil {
 		return f, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return f, err
 	}
 	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
-	if resp.StatusCode != 201 {
+	if resp.StatusCode != 200 {
 		var errObj ErrorCase
 		e := json.Unmarshal(body, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return nil, e
 		}
 		return nil, HandleResponseError(errObj)
 	}
@@ -88,15 +93,11 @@ func GetModfiles(modID int, gameID int, options map[string]string, user *User) (
 // GetModfile grabs a modfile and returns a File struct
 func GetModfile(fileID int, modID int, gameID int, user *User) (f *File, err error) {
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"?api_key="+user.APIKey(), nil)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/files/"+strconv.Itoa(fileID)+"?api_key="+user.APIKey(), nil)
 	if err != nil {
 		return f, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return f, err
 	}
@@ -109,7 +110,7 @@ func GetModfile(fileID int, modID int, gameID int, user *User) (f *File, err err
 		var errObj ErrorCase
 		e := json.Unmarshal(body, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return nil, e
 		}
 		return nil, HandleResponseError(errObj)
 	}
@@ -120,22 +121,23 @@ func GetModfile(fileID int, modID int, gameID int, user *User) (f *File, err err
 	return f, nil
 }
 
-// EditModfile sends a PUT request to edit a mod file
-func EditModfile(modID int, gameID int, options map[string]string, user *User) (f *File, err error) {
-	if user.OAuth2Token() == "" {
-		return f, errors.New("requires OAuth2 token")
+// EditModfile sends a PUT request to edit a mod file's version, changelog, active flag or metadata
+func EditModfile(fileID int, modID int, gameID int, options *EditModfileOptions, user *User) (f *File, err error) {
+	err = user.requireToken()
+	if err != nil {
+		return f, err
 	}
-	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	reqBody := ParseArgsBody(options)
-	req, err := http.NewRequest("PUT", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/files", strings.NewReader(reqBody.Encode()))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	err = options.Validate()
 	if err != nil {
 		return f, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
+	client := http.Client{Timeout: time.Duration(5 * time.Second)}
+	reqBody := options.values()
+	req, err := user.newRequest("PUT", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/files/"+strconv.Itoa(fileID), strings.NewReader(reqBody.Encode()))
+	if err != nil {
+		return f, err
 	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return f, err
 	}
@@ -148,7 +150,7 @@ func EditModfile(modID int, gameID int, options map[string]string, user *User) (
 		var errObj ErrorCase
 		e := json.Unmarshal(b, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return nil, e
 		}
 		return nil, HandleResponseError(errObj)
 	}
@@ -162,19 +164,16 @@ func EditModfile(modID int, gameID int, options map[string]string, user *User) (
 
 // DeleteModfile sends a DELETE request to delete a mod file
 func DeleteModfile(fileID int, modID int, gameID int, user *User) (err error) {
-	if user.OAuth2Token() == "" {
-		return errors.New("requires OAuth2 token")
+	err = user.requireToken()
+	if err != nil {
+		return err
 	}
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("DELETE", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/files/"+strconv.Itoa(fileID), nil)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequest("DELETE", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/files/"+strconv.Itoa(fileID), nil)
 	if err != nil {
 		return err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return err
 	}
@@ -187,45 +186,44 @@ func DeleteModfile(fileID int, modID int, gameID int, user *User) (err error) {
 		var errObj ErrorCase
 		e := json.Unmarshal(b, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return e
 		}
 		return HandleResponseError(errObj)
 	}
 	return nil
 }
 
-// AddModfile sends a POST request to upload a mod file
-func (user *User) AddModfile(modID int, gameID int, fp string, options map[string]string) (f *File, err error) {
-	if user.OAuth2Token() == "" {
-		return f, errors.New("requires OAuth2 token")
+// AddModfile sends a POST request to upload the mod file in options
+func (user *User) AddModfile(modID int, gameID int, options *AddModfileOptions) (f *File, err error) {
+	err = user.requireToken()
+	if err != nil {
+		return f, err
 	}
-	file, err := os.Open(fp)
+	err = options.Validate()
 	if err != nil {
 		return f, err
 	}
-	defer file.Close()
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
-	part, err := writer.CreateFormFile("filedata", filepath.Base(fp))
+	err = writeFile(writer, "filedata", options.Filedata)
 	if err != nil {
 		return f, err
 	}
-	_, err = io.Copy(part, file)
-	if options != nil {
-		for k, v := range options {
-			_ = writer.WriteField(k, v)
-		}
+	err = writeFields(writer, options.values())
+	if err != nil {
+		return f, err
 	}
-	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/files", body)
-	req.Header.Set("Content-Type", "multipart/form-data")
+	err = writer.Close()
 	if err != nil {
 		return f, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
+	client := http.Client{Timeout: time.Duration(5 * time.Second)}
+	req, err := user.newRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/files", body)
+	if err != nil {
+		return f, err
 	}
-	resp, err := client.Do(req)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return f, err
 	}
@@ -238,7 +236,7 @@ func (user *User) AddModfile(modID int, gameID int, fp string, options map[strin
 		var errObj ErrorCase
 		e := json.Unmarshal(b, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return nil, e
 		}
 		return nil, HandleResponseError(errObj)
 	}
@@ -248,3 +246,53 @@ func (user *User) AddModfile(modID int, gameID int, fp string, options map[strin
 	}
 	return f, err
 }
+
+// SetPrimaryModfile makes fileID the modfile downloaded for the mod
+func (user *User) SetPrimaryModfile(fileID, modID, gameID int) (res *Mod, err error) {
+	return user.EditMod(modID, gameID, &EditModOptions{Modfile: &fileID})
+}
+
+// ManageModfilePlatforms approves and denies a modfile for the given platforms
+func (user *User) ManageModfilePlatforms(fileID, modID, gameID int, approved, denied []Platform) (f *File, err error) {
+	err = user.requireToken()
+	if err != nil {
+		return f, err
+	}
+	if len(approved) == 0 && len(denied) == 0 {
+		return f, errors.New("must approve or deny at least one platform")
+	}
+	reqBody := url.Values{}
+	for _, p := range approved {
+		reqBody.Add("approved[]", string(p))
+	}
+	for _, p := range denied {
+		reqBody.Add("denied[]", string(p))
+	}
+	client := http.Client{Timeout: time.Duration(5 * time.Second)}
+	req, err := user.newRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/files/"+strconv.Itoa(fileID)+"/platforms", strings.NewReader(reqBody.Encode()))
+	if err != nil {
+		return f, err
+	}
+	resp, err := user.do(&client, req)
+	if err != nil {
+		return f, err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return f, err
+	}
+	if resp.StatusCode != 200 {
+		var errObj ErrorCase
+		e := json.Unmarshal(b, &errObj)
+		if e != nil {
+			return nil, e
+		}
+		return nil, HandleResponseError(errObj)
+	}
+	err = json.Unmarshal(b, &f)
+	if err != nil {
+		return f, err
+	}
+	return f, nil
+}
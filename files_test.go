@@ -0,0 +1,34 @@
+package gomodio
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestModfileUndecodableErrorBody(t *testing.T) {
+	user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>Bad Gateway</html>"))
+	})
+	user.SetOAuth2Token("token")
+	calls := map[string]func() error{
+		"GetModfiles": func() error {
+			_, err := GetModfiles(2, 1, nil, user)
+			return err
+		},
+		"GetModfile": func() error {
+			_, err := GetModfile(3, 2, 1, user)
+			return err
+		},
+		"EditModfile": func() error {
+			_, err := EditModfile(3, 2, 1, &EditModfileOptions{Version: String("1.0.1")}, user)
+			return err
+		},
+		"DeleteModfile": func() error { return DeleteModfile(3, 2, 1, user) },
+	}
+	for name, call := range calls {
+		if err := call(); err == nil {
+			t.Errorf("%s returned no error for a 502 HTML body", name)
+		}
+	}
+}
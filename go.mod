@@ -1,3 +1,5 @@
 module github.com/M4cs/gomodio
 
 go 1.15
+
+require gopkg.in/yaml.v2 v2.4.0
@@ -9,7 +9,7 @@ import (
 func ParseArgsGet(query map[string]string) string {
 	qSlice := []string{}
 	for k, v := range query {
-		qSlice = append(qSlice, k+"="+v)
+		qSlice = append(qSlice, url.QueryEscape(k)+"="+url.QueryEscape(v))
 	}
 	queryString := strings.Join(qSlice, "&")
 	return queryString
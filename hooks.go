@@ -0,0 +1,248 @@
+package gomodio
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Hook observes the requests a User sends to mod.io, for logging, metrics and tracing.
+// Hooks are called synchronously and must not modify the event
+type Hook interface {
+	// BeforeRequest is called before each attempt is sent
+	BeforeRequest(e *RequestEvent)
+	// AfterResponse is called once a response has been read, whatever its status
+	AfterResponse(e *RequestEvent)
+	// OnRetry is called when a rate limited or unavailable response is about to be retried
+	OnRetry(e *RequestEvent)
+	// OnError is called when the request could not be sent or its response read
+	OnError(e *RequestEvent)
+}
+
+// RequestEvent describes one attempt at a request
+type RequestEvent struct {
+	Method string
+	// Path is the endpoint with IDs replaced by placeholders, e.g. /games/{game_id}/mods/{mod_id}
+	Path string
+	// URL is the full request URL with the api_key redacted
+	URL string
+	// Attempt counts from 1 and increases on each retry
+	Attempt       int
+	Status        int
+	Duration      time.Duration
+	RequestBytes  int64
+	ResponseBytes int64
+	RateLimit     RateLimit
+	Err           error
+}
+
+// RateLimit holds the rate limit headers of a mod.io response
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	// RetryAfter is how long to wait before retrying once the limit is hit
+	RetryAfter time.Duration
+}
+
+// HookFuncs implements Hook with optional functions, leaving unset events ignored
+type HookFuncs struct {
+	BeforeRequestFunc func(e *RequestEvent)
+	AfterResponseFunc func(e *RequestEvent)
+	OnRetryFunc       func(e *RequestEvent)
+	OnErrorFunc       func(e *RequestEvent)
+}
+
+// BeforeRequest calls BeforeRequestFunc when set
+func (h HookFuncs) BeforeRequest(e *RequestEvent) {
+	if h.BeforeRequestFunc != nil {
+		h.BeforeRequestFunc(e)
+	}
+}
+
+// AfterResponse calls AfterResponseFunc when set
+func (h HookFuncs) AfterResponse(e *RequestEvent) {
+	if h.AfterResponseFunc != nil {
+		h.AfterResponseFunc(e)
+	}
+}
+
+// OnRetry calls OnRetryFunc when set
+func (h HookFuncs) OnRetry(e *RequestEvent) {
+	if h.OnRetryFunc != nil {
+		h.OnRetryFunc(e)
+	}
+}
+
+// OnError calls OnErrorFunc when set
+func (h HookFuncs) OnError(e *RequestEvent) {
+	if h.OnErrorFunc != nil {
+		h.OnErrorFunc(e)
+	}
+}
+
+// AddHook registers a hook called for every request the User sends
+func (u *User) AddHook(h Hook) {
+	u.hooks = append(u.hooks, h)
+}
+
+// SetMaxRetries sets how many times a request answered with 429 Too Many Requests
+// or 503 Service Unavailable is retried. Only idempotent requests are retried, so
+// POST writes are never sent twice. Zero, the default, disables retries
+func (u *User) SetMaxRetries(n int) {
+	u.maxRetries = n
+}
+
+// maxRetryWait caps how long a retry waits when mod.io asks for a longer delay
+const maxRetryWait = 60 * time.Second
+
+// do sends req with client, serving it from the User's cache when one is set.
+// The returned response's body is fully read and can be read again
+func (u *User) do(client *http.Client, req *http.Request) (*http.Response, error) {
+	if u.cache != nil {
+		return u.doCached(client, req)
+	}
+	return u.send(client, req)
+}
+
+// send sends req with client, calling the User's hooks and retrying rate limited requests
+func (u *User) send(client *http.Client, req *http.Request) (*http.Response, error) {
+	var reqBytes int64
+	if req.ContentLength > 0 {
+		reqBytes = req.ContentLength
+	}
+	if u.transport != nil {
+		c := *client
+		c.Transport = u.transport
+		client = &c
+	}
+	path := pathTemplate(req.URL)
+	redacted := RedactURL(req.URL.String())
+	for attempt := 1; ; attempt++ {
+		e := &RequestEvent{
+			Method:       req.Method,
+			Path:         path,
+			URL:          redacted,
+			Attempt:      attempt,
+			RequestBytes: reqBytes,
+		}
+		u.runHooks(Hook.BeforeRequest, e)
+		start := time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			e.Duration = time.Since(start)
+			e.Err = err
+			u.runHooks(Hook.OnError, e)
+			return nil, err
+		}
+		b, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		e.Duration = time.Since(start)
+		e.Status = resp.StatusCode
+		e.ResponseBytes = int64(len(b))
+		e.RateLimit = parseRateLimit(resp.Header)
+		if err != nil {
+			e.Err = err
+			u.runHooks(Hook.OnError, e)
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+		u.runHooks(Hook.AfterResponse, e)
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+		if !retryable || attempt > u.maxRetries || !idempotent(req.Method) || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			req.Body = body
+		}
+		u.runHooks(Hook.OnRetry, e)
+		wait := e.RateLimit.RetryAfter
+		if wait <= 0 {
+			wait = time.Duration(attempt) * time.Second
+		}
+		if wait > maxRetryWait {
+			wait = maxRetryWait
+		}
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// idempotent reports whether a request with method can safely be sent again
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func (u *User) runHooks(call func(Hook, *RequestEvent), e *RequestEvent) {
+	for _, h := range u.hooks {
+		call(h, e)
+	}
+}
+
+func parseRateLimit(h http.Header) RateLimit {
+	var rl RateLimit
+	rl.Limit, _ = strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	rl.Remaining, _ = strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	retry := h.Get("X-RateLimit-RetryAfter")
+	if retry == "" {
+		retry = h.Get("Retry-After")
+	}
+	if secs, err := strconv.Atoi(retry); err == nil {
+		rl.RetryAfter = time.Duration(secs) * time.Second
+	}
+	return rl
+}
+
+// pathPlaceholders names the ID following each collection in a mod.io path
+var pathPlaceholders = map[string]string{
+	"games":    "{game_id}",
+	"mods":     "{mod_id}",
+	"files":    "{file_id}",
+	"comments": "{comment_id}",
+	"users":    "{user_id}",
+}
+
+// pathTemplate returns the endpoint of u without the API version, with IDs replaced by placeholders
+func pathTemplate(u *url.URL) string {
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) > 0 && segments[0] == "v1" {
+		segments = segments[1:]
+	}
+	for i := 1; i < len(segments); i++ {
+		if _, err := strconv.Atoi(segments[i]); err != nil {
+			continue
+		}
+		if p, ok := pathPlaceholders[segments[i-1]]; ok {
+			segments[i] = p
+		} else {
+			segments[i] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+var (
+	apiKeyPattern = regexp.MustCompile(`(?i)(api_key=)[^&\s]*`)
+	bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)[^\s"]+`)
+)
+
+// RedactURL replaces the api_key query parameter and any bearer token in s, so it can be logged
+func RedactURL(s string) string {
+	s = apiKeyPattern.ReplaceAllString(s, "${1}REDACTED")
+	return bearerPattern.ReplaceAllString(s, "${1}REDACTED")
+}
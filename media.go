@@ -3,13 +3,10 @@ package gomodio
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"io"
 	"io/ioutil"
-	"log"
 	"mime/multipart"
 	"net/http"
-	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -24,24 +21,21 @@ type Message struct {
 }
 
 // DeleteModMedia deletes mod media
-func (user *User) DeleteModMedia(modID, gameID int, options map[string]string) (err error) {
-	var reqBody url.Values
-	if options == nil {
-		return errors.New("must provide options. cannot be nil")
+func (user *User) DeleteModMedia(modID, gameID int, options *DeleteModMediaOptions) (err error) {
+	err = user.requireToken()
+	if err != nil {
+		return err
 	}
-	for k, v := range options {
-		reqBody.Add(k, v)
+	err = options.Validate()
+	if err != nil {
+		return err
 	}
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/media", strings.NewReader(reqBody.Encode()))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequest("DELETE", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/media", strings.NewReader(options.values().Encode()))
 	if err != nil {
 		return err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return err
 	}
@@ -54,7 +48,7 @@ func (user *User) DeleteModMedia(modID, gameID int, options map[string]string) (
 		var errObj ErrorCase
 		e := json.Unmarshal(b, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return e
 		}
 		return HandleResponseError(errObj)
 	}
@@ -62,37 +56,32 @@ func (user *User) DeleteModMedia(modID, gameID int, options map[string]string) (
 }
 
 // AddModMedia adds mod media
-func (user *User) AddModMedia(modID, gameID int, options map[string]string) (msg *Message, err error) {
+func (user *User) AddModMedia(modID, gameID int, options *AddModMediaOptions) (msg *Message, err error) {
+	err = user.requireToken()
+	if err != nil {
+		return nil, err
+	}
+	err = options.Validate()
+	if err != nil {
+		return nil, err
+	}
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
-	if options != nil {
-		for k, v := range options {
-			if k == "logo" || k == "images" {
-				file, err := os.Open(v)
-				if err != nil {
-					return nil, err
-				}
-				defer file.Close()
-				part, err := writer.CreateFormFile(k, filepath.Base(v))
-				if err != nil {
-					return nil, err
-				}
-				_, err = io.Copy(part, file)
-			} else {
-				_ = writer.WriteField(k, v)
-			}
-		}
+	err = options.write(writer)
+	if err != nil {
+		return nil, err
 	}
-	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/media", body)
-	req.Header.Set("Content-Type", "multipart/form-data")
+	err = writer.Close()
 	if err != nil {
 		return nil, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
+	client := http.Client{Timeout: time.Duration(5 * time.Second)}
+	req, err := user.newRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/media", body)
+	if err != nil {
+		return nil, err
 	}
-	resp, err := client.Do(req)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return nil, err
 	}
@@ -101,11 +90,11 @@ func (user *User) AddModMedia(modID, gameID int, options map[string]string) (msg
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode != 200 {
+	if resp.StatusCode != 201 {
 		var errObj ErrorCase
 		e := json.Unmarshal(b, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return nil, e
 		}
 		return nil, HandleResponseError(errObj)
 	}
@@ -118,6 +107,10 @@ func (user *User) AddModMedia(modID, gameID int, options map[string]string) (msg
 
 // AddGameMedia adds game media
 func (user *User) AddGameMedia(logo, icon, header string, gameID int) (msg *Message, err error) {
+	err = user.requireToken()
+	if err != nil {
+		return nil, err
+	}
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 	file, err := os.Open(logo)
@@ -150,16 +143,20 @@ func (user *User) AddGameMedia(logo, icon, header string, gameID int) (msg *Mess
 		return nil, err
 	}
 	_, err = io.Copy(part2, file2)
-	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/media", body)
-	req.Header.Set("Content-Type", "multipart/form-data")
 	if err != nil {
 		return nil, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	client := http.Client{Timeout: time.Duration(5 * time.Second)}
+	req, err := user.newRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/media", body)
+	if err != nil {
+		return nil, err
 	}
-	resp, err := client.Do(req)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return nil, err
 	}
@@ -172,7 +169,7 @@ func (user *User) AddGameMedia(logo, icon, header string, gameID int) (msg *Mess
 		var errObj ErrorCase
 		e := json.Unmarshal(b, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return nil, e
 		}
 		return nil, HandleResponseError(errObj)
 	}
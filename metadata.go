@@ -2,45 +2,51 @@ package gomodio
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
-// ModKVP represents a mod's KVP metadata
-type ModKVP struct {
+// MetadataKVP represents a mod's KVP metadata
+type MetadataKVP struct {
 	Metakey   string `json:"metakey"`
 	Metavalue string `json:"metavalue"`
 }
 
+// ModKVP is the previous name of MetadataKVP
+type ModKVP = MetadataKVP
+
 // ModMetadata respesents multiple KVP metadata objects
 type ModMetadata struct {
-	Data         []ModKVP `json:"data"`
-	ResultCount  int      `json:"result_count"`
-	ResultLimit  int      `json:"result_limit"`
-	ResultTotal  int      `json:"result_total"`
-	ResultOffset int      `json:"result_offset"`
+	Data         []MetadataKVP `json:"data"`
+	ResultCount  int           `json:"result_count"`
+	ResultLimit  int           `json:"result_limit"`
+	ResultTotal  int           `json:"result_total"`
+	ResultOffset int           `json:"result_offset"`
 }
 
 // DeleteModMetadata deletes a mod's metadata
 func (u *User) DeleteModMetadata(metadata []string, modID, gameID int) (err error) {
+	err = u.requireToken()
+	if err != nil {
+		return err
+	}
 	reqBody := url.Values{
-		"metadata": {"[\"" + strings.Join(metadata, "\",\"") + "\"]"},
+		"metadata[]": metadata,
 	}
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("DELETE", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"mods/"+strconv.Itoa(modID)+"/metadetakvp?api_key="+u.APIKey(), strings.NewReader(reqBody.Encode()))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := u.newRequest("DELETE", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/metadatakvp?api_key="+u.APIKey(), strings.NewReader(reqBody.Encode()))
 	if err != nil {
 		return err
 	}
-	if u.OAuth2Token() != "" || len(u.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+u.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := u.do(&client, req)
 	if err != nil {
 		return err
 	}
@@ -50,7 +56,7 @@ func (u *User) DeleteModMetadata(metadata []string, modID, gameID int) (err erro
 		var errObj ErrorCase
 		e := json.Unmarshal(body, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return e
 		}
 		return HandleResponseError(errObj)
 	}
@@ -62,19 +68,19 @@ func (u *User) DeleteModMetadata(metadata []string, modID, gameID int) (err erro
 
 // AddModMetadata adds metadata to a mod
 func (u *User) AddModMetadata(metadata []string, modID, gameID int) (m *Message, err error) {
+	err = u.requireToken()
+	if err != nil {
+		return nil, err
+	}
 	reqBody := url.Values{
-		"metadata": {"[\"" + strings.Join(metadata, "\",\"") + "\"]"},
+		"metadata[]": metadata,
 	}
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"mods/"+strconv.Itoa(modID)+"/metadetakvp?api_key="+u.APIKey(), strings.NewReader(reqBody.Encode()))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := u.newRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/metadatakvp?api_key="+u.APIKey(), strings.NewReader(reqBody.Encode()))
 	if err != nil {
 		return nil, err
 	}
-	if u.OAuth2Token() != "" || len(u.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+u.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := u.do(&client, req)
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +90,7 @@ func (u *User) AddModMetadata(metadata []string, modID, gameID int) (m *Message,
 		var errObj ErrorCase
 		e := json.Unmarshal(body, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return nil, e
 		}
 		return nil, HandleResponseError(errObj)
 	}
@@ -101,15 +107,11 @@ func (u *User) AddModMetadata(metadata []string, modID, gameID int) (m *Message,
 // GetModMetadata gets a mod's metadata
 func (u *User) GetModMetadata(modID, gameID int) (mm *ModMetadata, err error) {
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"mods/"+strconv.Itoa(modID)+"/metadetakvp?api_key="+u.APIKey(), nil)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := u.newRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/metadatakvp?api_key="+u.APIKey(), nil)
 	if err != nil {
 		return nil, err
 	}
-	if u.OAuth2Token() != "" || len(u.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+u.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := u.do(&client, req)
 	if err != nil {
 		return nil, err
 	}
@@ -119,7 +121,7 @@ func (u *User) GetModMetadata(modID, gameID int) (mm *ModMetadata, err error) {
 		var errObj ErrorCase
 		e := json.Unmarshal(body, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return nil, e
 		}
 		return nil, HandleResponseError(errObj)
 	}
@@ -132,3 +134,231 @@ func (u *User) GetModMetadata(modID, gameID int) (mm *ModMetadata, err error) {
 	}
 	return mm, nil
 }
+
+// Metadata limits enforced by mod.io
+const (
+	maxMetakeyLength   = 255
+	maxMetavalueLength = 255
+)
+
+// MetadataFilter builds the metadata_kvp filter of GetMods. Mods must match
+// every KVP; supplying a key more than once requires the mod to have each value
+func MetadataFilter(kvps ...ModKVP) (string, error) {
+	if len(kvps) == 0 {
+		return "", errors.New("metadata filter requires at least one key-value pair")
+	}
+	err := ValidateMetadata(kvps)
+	if err != nil {
+		return "", err
+	}
+	pairs := make([]string, len(kvps))
+	for i, kvp := range kvps {
+		if strings.Contains(kvp.Metavalue, ",") {
+			return "", fmt.Errorf("metadata filter value of %q cannot contain ','", kvp.Metakey)
+		}
+		pairs[i] = kvp.Metakey + ":" + kvp.Metavalue
+	}
+	return strings.Join(pairs, ","), nil
+}
+
+// MarshalMetadata converts the fields of the struct v into metadata KVPs.
+// The key is taken from the field's `modio:"key"` tag, or the field name when
+// untagged, and `modio:"-"` skips the field. Slice fields produce one KVP per
+// element so a key can carry several values
+func MarshalMetadata(v interface{}) ([]ModKVP, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil, errors.New("metadata must be a struct or pointer to struct")
+	}
+	var kvps []ModKVP
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		key, ok := metadataKey(rt.Field(i))
+		if !ok {
+			continue
+		}
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Slice {
+			for j := 0; j < fv.Len(); j++ {
+				s, err := formatMetavalue(fv.Index(j))
+				if err != nil {
+					return nil, fmt.Errorf("metadata %s: %v", key, err)
+				}
+				kvps = append(kvps, ModKVP{Metakey: key, Metavalue: s})
+			}
+			continue
+		}
+		s, err := formatMetavalue(fv)
+		if err != nil {
+			return nil, fmt.Errorf("metadata %s: %v", key, err)
+		}
+		kvps = append(kvps, ModKVP{Metakey: key, Metavalue: s})
+	}
+	return kvps, ValidateMetadata(kvps)
+}
+
+// UnmarshalMetadata fills the struct pointed to by v from metadata KVPs using
+// the same tags as MarshalMetadata. Keys without a matching field are ignored
+func UnmarshalMetadata(kvps []ModKVP, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("metadata must be unmarshaled into a pointer to struct")
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	fields := map[string]int{}
+	for i := 0; i < rt.NumField(); i++ {
+		if key, ok := metadataKey(rt.Field(i)); ok {
+			fields[key] = i
+		}
+	}
+	for _, kvp := range kvps {
+		i, ok := fields[kvp.Metakey]
+		if !ok {
+			continue
+		}
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Slice {
+			ev := reflect.New(fv.Type().Elem()).Elem()
+			err := parseMetavalue(ev, kvp.Metavalue)
+			if err != nil {
+				return fmt.Errorf("metadata %s: %v", kvp.Metakey, err)
+			}
+			fv.Set(reflect.Append(fv, ev))
+			continue
+		}
+		err := parseMetavalue(fv, kvp.Metavalue)
+		if err != nil {
+			return fmt.Errorf("metadata %s: %v", kvp.Metakey, err)
+		}
+	}
+	return nil
+}
+
+// ValidateMetadata checks KVPs against mod.io's key and value limits
+func ValidateMetadata(kvps []ModKVP) error {
+	for _, kvp := range kvps {
+		if kvp.Metakey == "" {
+			return errors.New("metadata key cannot be empty")
+		}
+		if strings.Contains(kvp.Metakey, ":") {
+			return fmt.Errorf("metadata key %q cannot contain ':'", kvp.Metakey)
+		}
+		if utf8.RuneCountInString(kvp.Metakey) > maxMetakeyLength {
+			return fmt.Errorf("metadata key %q must be at most %d characters", kvp.Metakey, maxMetakeyLength)
+		}
+		if utf8.RuneCountInString(kvp.Metavalue) > maxMetavalueLength {
+			return fmt.Errorf("metadata value of %q must be at most %d characters", kvp.Metakey, maxMetavalueLength)
+		}
+	}
+	return nil
+}
+
+// SetModMetadata makes the mod's metadata match desired, deleting and adding
+// only the KVPs that differ
+func (u *User) SetModMetadata(desired []ModKVP, modID, gameID int) (err error) {
+	err = ValidateMetadata(desired)
+	if err != nil {
+		return err
+	}
+	mod, err := u.GetMod(modID, gameID, nil)
+	if err != nil {
+		return err
+	}
+	current := map[string]bool{}
+	for _, kvp := range mod.MetadataKvp {
+		current[kvp.Metakey+":"+kvp.Metavalue] = true
+	}
+	wanted := map[string]bool{}
+	var added, removed []string
+	for _, kvp := range desired {
+		s := kvp.Metakey + ":" + kvp.Metavalue
+		if !wanted[s] && !current[s] {
+			added = append(added, s)
+		}
+		wanted[s] = true
+	}
+	for _, kvp := range mod.MetadataKvp {
+		s := kvp.Metakey + ":" + kvp.Metavalue
+		if !wanted[s] {
+			removed = append(removed, s)
+		}
+	}
+	if len(removed) > 0 {
+		err = u.DeleteModMetadata(removed, modID, gameID)
+		if err != nil {
+			return err
+		}
+	}
+	if len(added) > 0 {
+		_, err = u.AddModMetadata(added, modID, gameID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func metadataKey(f reflect.StructField) (string, bool) {
+	if f.PkgPath != "" {
+		return "", false
+	}
+	tag := f.Tag.Get("modio")
+	if tag == "-" {
+		return "", false
+	}
+	if tag == "" {
+		return f.Name, true
+	}
+	return tag, true
+}
+
+func formatMetavalue(v reflect.Value) (string, error) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
+
+func parseMetavalue(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package gomodio
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testMetadata struct {
+	Engine  string   `modio:"engine"`
+	Maps    []string `modio:"map"`
+	Players int      `modio:"max_players"`
+	Ranked  bool
+	Scale   float64 `modio:"scale"`
+	Skipped string  `modio:"-"`
+	secret  string
+}
+
+func TestMarshalMetadata(t *testing.T) {
+	v := testMetadata{
+		Engine:  "4.2",
+		Maps:    []string{"dust", "inferno"},
+		Players: 16,
+		Ranked:  true,
+		Scale:   1.5,
+		Skipped: "ignored",
+		secret:  "ignored",
+	}
+	got, err := MarshalMetadata(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ModKVP{
+		{Metakey: "engine", Metavalue: "4.2"},
+		{Metakey: "map", Metavalue: "dust"},
+		{Metakey: "map", Metavalue: "inferno"},
+		{Metakey: "max_players", Metavalue: "16"},
+		{Metakey: "Ranked", Metavalue: "true"},
+		{Metakey: "scale", Metavalue: "1.5"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MarshalMetadata = %+v, want %+v", got, want)
+	}
+}
+
+func TestMarshalMetadataErrors(t *testing.T) {
+	if _, err := MarshalMetadata("not a struct"); err == nil {
+		t.Error("non-struct accepted")
+	}
+	if _, err := MarshalMetadata(struct{ M map[string]int }{}); err == nil {
+		t.Error("unsupported field type accepted")
+	}
+	if _, err := MarshalMetadata(struct {
+		V string `modio:"a:b"`
+	}{}); err == nil {
+		t.Error("key containing ':' accepted")
+	}
+}
+
+func TestUnmarshalMetadata(t *testing.T) {
+	kvps := []ModKVP{
+		{Metakey: "engine", Metavalue: "4.2"},
+		{Metakey: "map", Metavalue: "dust"},
+		{Metakey: "map", Metavalue: "inferno"},
+		{Metakey: "max_players", Metavalue: "16"},
+		{Metakey: "Ranked", Metavalue: "true"},
+		{Metakey: "scale", Metavalue: "1.5"},
+		{Metakey: "Skipped", Metavalue: "x"},
+		{Metakey: "secret", Metavalue: "x"},
+		{Metakey: "unknown", Metavalue: "x"},
+	}
+	var got testMetadata
+	err := UnmarshalMetadata(kvps, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := testMetadata{Engine: "4.2", Maps: []string{"dust", "inferno"}, Players: 16, Ranked: true, Scale: 1.5}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UnmarshalMetadata = %+v, want %+v", got, want)
+	}
+}
+
+func TestUnmarshalMetadataErrors(t *testing.T) {
+	var v testMetadata
+	if err := UnmarshalMetadata(nil, v); err == nil {
+		t.Error("non-pointer accepted")
+	}
+	tests := []ModKVP{
+		{Metakey: "max_players", Metavalue: "many"},
+		{Metakey: "Ranked", Metavalue: "maybe"},
+		{Metakey: "scale", Metavalue: "big"},
+	}
+	for _, kvp := range tests {
+		if err := UnmarshalMetadata([]ModKVP{kvp}, &v); err == nil {
+			t.Errorf("%s=%q parsed without error", kvp.Metakey, kvp.Metavalue)
+		}
+	}
+}
+
+func TestValidateMetadata(t *testing.T) {
+	tests := []struct {
+		kvp ModKVP
+		ok  bool
+	}{
+		{ModKVP{Metakey: "engine", Metavalue: "4.2"}, true},
+		{ModKVP{Metakey: "", Metavalue: "x"}, false},
+		{ModKVP{Metakey: "a:b", Metavalue: "x"}, false},
+		{ModKVP{Metakey: strings.Repeat("k", maxMetakeyLength), Metavalue: "x"}, true},
+		{ModKVP{Metakey: strings.Repeat("k", maxMetakeyLength+1), Metavalue: "x"}, false},
+		{ModKVP{Metakey: "k", Metavalue: strings.Repeat("v", maxMetavalueLength)}, true},
+		{ModKVP{Metakey: "k", Metavalue: strings.Repeat("v", maxMetavalueLength+1)}, false},
+	}
+	for _, tt := range tests {
+		err := ValidateMetadata([]ModKVP{tt.kvp})
+		if (err == nil) != tt.ok {
+			t.Errorf("ValidateMetadata(%.20q) = %v, want ok %v", tt.kvp.Metakey, err, tt.ok)
+		}
+	}
+}
+
+func TestSetModMetadataRequests(t *testing.T) {
+	var requests []string
+	user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(b))
+		requests = append(requests, r.Method+" "+strings.Join(form["metadata[]"], ","))
+		switch r.Method {
+		case "GET":
+			w.Write([]byte(`{"id":2,"metadata_kvp":[{"metakey":"engine","metavalue":"4.1"},{"metakey":"mode","metavalue":"pvp"}]}`))
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		case "POST":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"code":201,"message":"added"}`))
+		}
+	})
+	user.SetOAuth2Token("token")
+	err := user.SetModMetadata([]ModKVP{{Metakey: "engine", Metavalue: "4.2"}, {Metakey: "mode", Metavalue: "pvp"}}, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"GET ", "DELETE engine:4.1", "POST engine:4.2"}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+}
+
+func TestMetadataUndecodableErrorBody(t *testing.T) {
+	user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>Bad Gateway</html>"))
+	})
+	user.SetOAuth2Token("token")
+	if _, err := user.GetModMetadata(2, 1); err == nil {
+		t.Error("GetModMetadata returned no error")
+	}
+	if _, err := user.AddModMetadata([]string{"a:b"}, 2, 1); err == nil {
+		t.Error("AddModMetadata returned no error")
+	}
+	if err := user.DeleteModMetadata([]string{"a:b"}, 2, 1); err == nil {
+		t.Error("DeleteModMetadata returned no error")
+	}
+}
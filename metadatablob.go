@@ -0,0 +1,78 @@
+package gomodio
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// EncodeMetadataBlob encodes v as JSON for use as a mod or modfile metadata_blob.
+// An error is returned when the result exceeds mod.io's maximum blob size
+func EncodeMetadataBlob(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	if utf8.RuneCount(b) > maxMetadataBlobLength {
+		return "", fmt.Errorf("metadata_blob must be at most %d characters", maxMetadataBlobLength)
+	}
+	return string(b), nil
+}
+
+// DecodeMetadataBlob decodes a JSON metadata_blob into v. An empty blob leaves v unchanged
+func DecodeMetadataBlob(blob string, v interface{}) error {
+	if blob == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(blob), v)
+}
+
+// DecodeMetadataBlob decodes the mod's metadata_blob into v
+func (m *Mod) DecodeMetadataBlob(v interface{}) error {
+	return DecodeMetadataBlob(m.MetadataBlob, v)
+}
+
+// DecodeMetadataBlob decodes the modfile's metadata_blob into v
+func (f *File) DecodeMetadataBlob(v interface{}) error {
+	return DecodeMetadataBlob(f.MetadataBlob, v)
+}
+
+// SetMetadataBlob encodes v as the metadata_blob of the new mod
+func (o *AddModOptions) SetMetadataBlob(v interface{}) error {
+	blob, err := EncodeMetadataBlob(v)
+	if err != nil {
+		return err
+	}
+	o.MetadataBlob = &blob
+	return nil
+}
+
+// SetMetadataBlob encodes v as the mod's new metadata_blob
+func (o *EditModOptions) SetMetadataBlob(v interface{}) error {
+	blob, err := EncodeMetadataBlob(v)
+	if err != nil {
+		return err
+	}
+	o.MetadataBlob = &blob
+	return nil
+}
+
+// SetMetadataBlob encodes v as the metadata_blob of the new modfile
+func (o *AddModfileOptions) SetMetadataBlob(v interface{}) error {
+	blob, err := EncodeMetadataBlob(v)
+	if err != nil {
+		return err
+	}
+	o.MetadataBlob = &blob
+	return nil
+}
+
+// SetMetadataBlob encodes v as the modfile's new metadata_blob
+func (o *EditModfileOptions) SetMetadataBlob(v interface{}) error {
+	blob, err := EncodeMetadataBlob(v)
+	if err != nil {
+		return err
+	}
+	o.MetadataBlob = &blob
+	return nil
+}
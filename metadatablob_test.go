@@ -0,0 +1,91 @@
+package gomodio
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type blobConfig struct {
+	Engine   string   `json:"engine"`
+	MinBuild int      `json:"min_build"`
+	Maps     []string `json:"maps,omitempty"`
+}
+
+func TestMetadataBlobRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   blobConfig
+		blob string
+	}{
+		{"full", blobConfig{Engine: "4.2", MinBuild: 1200, Maps: []string{"dust", "ruins"}}, `{"engine":"4.2","min_build":1200,"maps":["dust","ruins"]}`},
+		{"omitempty", blobConfig{Engine: "4.2"}, `{"engine":"4.2","min_build":0}`},
+	}
+	for _, tt := range tests {
+		blob, err := EncodeMetadataBlob(tt.in)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if blob != tt.blob {
+			t.Errorf("%s: blob = %s, want %s", tt.name, blob, tt.blob)
+		}
+		var out blobConfig
+		if err := (&Mod{MetadataBlob: blob}).DecodeMetadataBlob(&out); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(out, tt.in) {
+			t.Errorf("%s: decoded %+v, want %+v", tt.name, out, tt.in)
+		}
+	}
+}
+
+func TestMetadataBlobErrors(t *testing.T) {
+	if _, err := EncodeMetadataBlob(strings.Repeat("a", maxMetadataBlobLength)); err == nil {
+		t.Error("blob over the size limit encoded")
+	}
+	if _, err := EncodeMetadataBlob(strings.Repeat("a", maxMetadataBlobLength-2)); err != nil {
+		t.Errorf("blob at the size limit: %v", err)
+	}
+	if _, err := EncodeMetadataBlob(func() {}); err == nil {
+		t.Error("unencodable value encoded")
+	}
+	out := blobConfig{Engine: "kept"}
+	if err := (&File{}).DecodeMetadataBlob(&out); err != nil || out.Engine != "kept" {
+		t.Errorf("empty blob: %+v, %v", out, err)
+	}
+	if err := DecodeMetadataBlob("not json", &out); err == nil {
+		t.Error("invalid blob decoded")
+	}
+}
+
+func TestSetMetadataBlob(t *testing.T) {
+	v := blobConfig{Engine: "4.2"}
+	want := `{"engine":"4.2","min_build":0}`
+	add := &AddModOptions{}
+	edit := &EditModOptions{}
+	addFile := &AddModfileOptions{}
+	editFile := &EditModfileOptions{}
+	for name, set := range map[string]func(interface{}) error{
+		"AddModOptions":      add.SetMetadataBlob,
+		"EditModOptions":     edit.SetMetadataBlob,
+		"AddModfileOptions":  addFile.SetMetadataBlob,
+		"EditModfileOptions": editFile.SetMetadataBlob,
+	} {
+		if err := set(v); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	for name, got := range map[string]*string{
+		"AddModOptions":      add.MetadataBlob,
+		"EditModOptions":     edit.MetadataBlob,
+		"AddModfileOptions":  addFile.MetadataBlob,
+		"EditModfileOptions": editFile.MetadataBlob,
+	} {
+		if got == nil || *got != want {
+			t.Errorf("%s.MetadataBlob = %v, want %s", name, got, want)
+		}
+	}
+	if err := add.SetMetadataBlob(strings.Repeat("a", maxMetadataBlobLength)); err == nil {
+		t.Error("oversized blob set")
+	}
+}
@@ -0,0 +1,181 @@
+package gomodio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// modPageSize is the number of mods requested per page when walking every mod of a game
+const modPageSize = 100
+
+// allVisibilities and allStatuses filter Get Mods to every mod, not only live public ones
+var (
+	allVisibilities = strconv.Itoa(int(VisibilityHidden)) + "," + strconv.Itoa(int(VisibilityPublic))
+	allStatuses     = strconv.Itoa(int(StatusNotAccepted)) + "," + strconv.Itoa(int(StatusAccepted)) + "," + strconv.Itoa(int(StatusDeleted))
+)
+
+// CommentFilter selects comments for moderation. Zero fields match every comment
+type CommentFilter struct {
+	// Since and Until bound the date the comment was added
+	Since time.Time
+	Until time.Time
+	// UserIDs and Usernames match the comment's author
+	UserIDs   []int
+	Usernames []string
+	// KarmaBelow matches comments with karma lower than the threshold
+	KarmaBelow *int
+	// Content matches the comment's text
+	Content *regexp.Regexp
+}
+
+// Match reports whether the comment passes every set field of the filter
+func (f *CommentFilter) Match(c Comment) bool {
+	if f == nil {
+		return true
+	}
+	added := c.DateAdded.Time()
+	if !f.Since.IsZero() && added.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && added.After(f.Until) {
+		return false
+	}
+	if len(f.UserIDs) > 0 || len(f.Usernames) > 0 {
+		found := false
+		for _, id := range f.UserIDs {
+			if c.User.ID == id {
+				found = true
+			}
+		}
+		for _, name := range f.Usernames {
+			if c.User.Username == name {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.KarmaBelow != nil && c.Karma >= *f.KarmaBelow {
+		return false
+	}
+	if f.Content != nil && !f.Content.MatchString(c.Content) {
+		return false
+	}
+	return true
+}
+
+// WalkGameComments calls fn for every comment on every mod of a game that matches
+// filter, including hidden and unapproved mods, which only game admins can list.
+// Walking stops at the first error returned by fn or when ctx is done
+func (user *User) WalkGameComments(ctx context.Context, gameID int, filter *CommentFilter, fn func(mod *Mod, c Comment) error) error {
+	for offset := 0; ; offset += modPageSize {
+		mods, err := user.getMods(ctx, gameID, map[string]string{
+			"visible-in": allVisibilities,
+			"status-in":  allStatuses,
+			"_limit":     strconv.Itoa(modPageSize),
+			"_offset":    strconv.Itoa(offset),
+		})
+		if err != nil {
+			return err
+		}
+		for i := range mods.Data {
+			mod := &mods.Data[i]
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			comments, err := user.getAllModComments(ctx, mod.ID, gameID, nil)
+			if err != nil {
+				return err
+			}
+			for _, c := range comments {
+				if !filter.Match(c) {
+					continue
+				}
+				err = fn(mod, c)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if len(mods.Data) == 0 || offset+len(mods.Data) >= mods.ResultTotal {
+			return nil
+		}
+	}
+}
+
+// ModerationAction is an audit log entry of a comment removed by a moderator
+type ModerationAction struct {
+	Date        Timestamp `json:"date"`
+	Moderator   string    `json:"moderator"`
+	ModeratorID int       `json:"moderator_id"`
+	GameID      int       `json:"game_id"`
+	ModID       int       `json:"mod_id"`
+	ModName     string    `json:"mod_name"`
+	CommentID   int       `json:"comment_id"`
+	AuthorID    int       `json:"author_id"`
+	Author      string    `json:"author"`
+	Karma       int       `json:"karma"`
+	Content     string    `json:"content"`
+	DryRun      bool      `json:"dry_run"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// ModerateComments deletes every comment of a game's mods matching filter. With dryRun
+// nothing is deleted. Each match is written to audit, when not nil, as a line of JSON.
+// A failed deletion is recorded in its action and moderation carries on. The moderator
+// is looked up from the OAuth2 token, falling back to the User's email for dry runs without one
+func (user *User) ModerateComments(ctx context.Context, gameID int, filter *CommentFilter, dryRun bool, audit io.Writer) (actions []ModerationAction, err error) {
+	moderator := &UserProfile{Username: user.Email()}
+	if !dryRun || user.OAuth2Token() != "" {
+		moderator, err = user.getAuthenticatedUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var enc *json.Encoder
+	if audit != nil {
+		enc = json.NewEncoder(audit)
+	}
+	failed := 0
+	err = user.WalkGameComments(ctx, gameID, filter, func(mod *Mod, c Comment) error {
+		action := ModerationAction{
+			Date:        NewTimestamp(time.Now()),
+			Moderator:   moderator.Username,
+			ModeratorID: moderator.ID,
+			GameID:      gameID,
+			ModID:       mod.ID,
+			ModName:     mod.Name,
+			CommentID:   c.ID,
+			AuthorID:    c.User.ID,
+			Author:      c.User.Username,
+			Karma:       c.Karma,
+			Content:     c.Content,
+			DryRun:      dryRun,
+		}
+		if !dryRun {
+			e := user.deleteModComment(ctx, c.ID, mod.ID, gameID)
+			if e != nil {
+				action.Error = e.Error()
+				failed++
+			}
+		}
+		actions = append(actions, action)
+		if enc != nil {
+			return enc.Encode(action)
+		}
+		return nil
+	})
+	if err != nil {
+		return actions, err
+	}
+	if failed > 0 {
+		return actions, fmt.Errorf("failed to delete %d of %d comments", failed, len(actions))
+	}
+	return actions, nil
+}
@@ -0,0 +1,74 @@
+package gomodio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestModerateCommentsVisitsHiddenMods(t *testing.T) {
+	var mu sync.Mutex
+	var deleted []string
+	user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/me":
+			w.Write([]byte(`{"id":9,"username":"mod-admin"}`))
+		case r.URL.Path == "/v1/games/1/mods":
+			q := r.URL.Query()
+			if q.Get("visible-in") != "0,1" || q.Get("status-in") != "0,1,3" {
+				t.Errorf("mods listed with visible-in=%q status-in=%q", q.Get("visible-in"), q.Get("status-in"))
+			}
+			w.Write([]byte(`{"data":[{"id":2,"name":"Hidden Mod","visible":0,"status":0}],"result_total":1}`))
+		case r.URL.Path == "/v1/games/1/mods/2/comments":
+			w.Write([]byte(`{"data":[{"id":5,"content":"buy gold http://spam"},{"id":6,"content":"nice mod"}],"result_total":2}`))
+		case r.Method == "DELETE":
+			mu.Lock()
+			deleted = append(deleted, r.URL.Path)
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	user.SetOAuth2Token("token")
+	filter := &CommentFilter{Content: regexp.MustCompile(`https?://`)}
+	actions, err := user.ModerateComments(context.Background(), 1, filter, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actions) != 1 || actions[0].CommentID != 5 || actions[0].ModName != "Hidden Mod" {
+		t.Fatalf("actions = %+v", actions)
+	}
+	if actions[0].Moderator != "mod-admin" || actions[0].ModeratorID != 9 {
+		t.Errorf("moderator = %q (%d)", actions[0].Moderator, actions[0].ModeratorID)
+	}
+	if len(deleted) != 1 || deleted[0] != "/v1/games/1/mods/2/comments/5" {
+		t.Errorf("deleted = %v", deleted)
+	}
+}
+
+func TestWalkGameCommentsCancel(t *testing.T) {
+	started := make(chan struct{})
+	user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		// Hold the request until the client gives up on it
+		<-r.Context().Done()
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	start := time.Now()
+	err := user.WalkGameComments(ctx, 1, nil, func(*Mod, Comment) error { return nil })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("walk took %v after cancellation", d)
+	}
+}
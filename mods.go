@@ -2,15 +2,12 @@ package gomodio
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
-	"io"
 	"io/ioutil"
-	"log"
 	"mime/multipart"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -27,112 +24,53 @@ type Mods struct {
 
 // Mod struct which maps to the JSON response of Get/Edit/Add/Delete Mod/s
 type Mod struct {
-	ID          int `json:"id"`
-	GameID      int `json:"game_id"`
-	Status      int `json:"status"`
-	Visible     int `json:"visible"`
-	SubmittedBy struct {
-		ID         int    `json:"id"`
-		NameID     string `json:"name_id"`
-		Username   string `json:"username"`
-		DateOnline int    `json:"date_online"`
-		Avatar     struct {
-			Filename     string `json:"filename"`
-			Original     string `json:"original"`
-			Thumb50X50   string `json:"thumb_50x50"`
-			Thumb100X100 string `json:"thumb_100x100"`
-		} `json:"avatar"`
-		Timezone   string `json:"timezone"`
-		Language   string `json:"language"`
-		ProfileURL string `json:"profile_url"`
-	} `json:"submitted_by"`
-	DateAdded      int `json:"date_added"`
-	DateUpdated    int `json:"date_updated"`
-	DateLive       int `json:"date_live"`
-	MaturityOption int `json:"maturity_option"`
-	Logo           struct {
-		Filename      string `json:"filename"`
-		Original      string `json:"original"`
-		Thumb320X180  string `json:"thumb_320x180"`
-		Thumb640X360  string `json:"thumb_640x360"`
-		Thumb1280X720 string `json:"thumb_1280x720"`
-	} `json:"logo"`
-	HomepageURL          string `json:"homepage_url"`
-	Name                 string `json:"name"`
-	NameID               string `json:"name_id"`
-	Summary              string `json:"summary"`
-	Description          string `json:"description"`
-	DescriptionPlaintext string `json:"description_plaintext"`
-	MetadataBlob         string `json:"metadata_blob"`
-	ProfileURL           string `json:"profile_url"`
+	ID                   int            `json:"id"`
+	GameID               int            `json:"game_id"`
+	Status               Status         `json:"status"`
+	Visible              Visibility     `json:"visible"`
+	SubmittedBy          UserProfile    `json:"submitted_by"`
+	DateAdded            Timestamp      `json:"date_added"`
+	DateUpdated          Timestamp      `json:"date_updated"`
+	DateLive             Timestamp      `json:"date_live"`
+	MaturityOption       MaturityOption `json:"maturity_option"`
+	Logo                 Logo           `json:"logo"`
+	HomepageURL          string         `json:"homepage_url"`
+	Name                 string         `json:"name"`
+	NameID               string         `json:"name_id"`
+	Summary              string         `json:"summary"`
+	Description          string         `json:"description"`
+	DescriptionPlaintext string         `json:"description_plaintext"`
+	MetadataBlob         string         `json:"metadata_blob"`
+	ProfileURL           string         `json:"profile_url"`
 	Media                struct {
 		Youtube   []string `json:"youtube"`
 		Sketchfab []string `json:"sketchfab"`
-		Images    []struct {
-			Filename     string `json:"filename"`
-			Original     string `json:"original"`
-			Thumb320X180 string `json:"thumb_320x180"`
-		} `json:"images"`
+		Images    []Image  `json:"images"`
 	} `json:"media"`
-	Modfile struct {
-		ID             int    `json:"id"`
-		ModID          int    `json:"mod_id"`
-		DateAdded      int    `json:"date_added"`
-		DateScanned    int    `json:"date_scanned"`
-		VirusStatus    int    `json:"virus_status"`
-		VirusPositive  int    `json:"virus_positive"`
-		VirustotalHash string `json:"virustotal_hash"`
-		Filesize       int    `json:"filesize"`
-		Filehash       struct {
-			Md5 string `json:"md5"`
-		} `json:"filehash"`
-		Filename     string `json:"filename"`
-		Version      string `json:"version"`
-		Changelog    string `json:"changelog"`
-		MetadataBlob string `json:"metadata_blob"`
-		Download     struct {
-			BinaryURL   string `json:"binary_url"`
-			DateExpires int    `json:"date_expires"`
-		} `json:"download"`
-	} `json:"modfile"`
-	MetadataKvp []struct {
-		Metakey   string `json:"metakey"`
-		Metavalue string `json:"metavalue"`
-	} `json:"metadata_kvp"`
-	Tags []struct {
-		Name      string `json:"name"`
-		DateAdded int    `json:"date_added"`
-	} `json:"tags"`
-	Stats struct {
-		ModID                     int     `json:"mod_id"`
-		PopularityRankPosition    int     `json:"popularity_rank_position"`
-		PopularityRankTotalMods   int     `json:"popularity_rank_total_mods"`
-		DownloadsTotal            int     `json:"downloads_total"`
-		SubscribersTotal          int     `json:"subscribers_total"`
-		RatingsTotal              int     `json:"ratings_total"`
-		RatingsPositive           int     `json:"ratings_positive"`
-		RatingsNegative           int     `json:"ratings_negative"`
-		RatingsPercentagePositive int     `json:"ratings_percentage_positive"`
-		RatingsWeightedAggregate  float64 `json:"ratings_weighted_aggregate"`
-		RatingsDisplayText        string  `json:"ratings_display_text"`
-		DateExpires               int     `json:"date_expires"`
-	} `json:"stats"`
+	Modfile     File          `json:"modfile"`
+	MetadataKvp []MetadataKVP `json:"metadata_kvp"`
+	Tags        []ModTag      `json:"tags"`
+	Stats       Stats         `json:"stats"`
 }
 
 // GetMods searches for mods and returns a Mods object
 func (user *User) GetMods(gameID int, query map[string]string) (res *Mods, err error) {
+	return user.getMods(context.Background(), gameID, query)
+}
+
+// getMods is GetMods bound to ctx
+func (user *User) getMods(ctx context.Context, gameID int, query map[string]string) (res *Mods, err error) {
+	if query == nil {
+		query = map[string]string{}
+	}
 	query["api_key"] = user.APIKey()
 	queryString := ParseArgsGet(query)
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods?"+queryString, nil)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequestWithContext(ctx, "GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods?"+queryString, nil)
 	if err != nil {
 		return res, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return res, err
 	}
@@ -142,7 +80,7 @@ func (user *User) GetMods(gameID int, query map[string]string) (res *Mods, err e
 		var errObj ErrorCase
 		e := json.Unmarshal(body, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return nil, e
 		}
 		return nil, HandleResponseError(errObj)
 	}
@@ -156,22 +94,60 @@ func (user *User) GetMods(gameID int, query map[string]string) (res *Mods, err e
 	return res, nil
 }
 
+// GetModsByMetadata searches for mods having every one of the metadata KVPs
+func (user *User) GetModsByMetadata(gameID int, query map[string]string, kvps ...ModKVP) (res *Mods, err error) {
+	filter, err := MetadataFilter(kvps...)
+	if err != nil {
+		return nil, err
+	}
+	q := map[string]string{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q["metadata_kvp"] = filter
+	return user.GetMods(gameID, q)
+}
+
 // EditMod edits a mod
-func (user *User) EditMod(modID int, gameID int, options map[string]string) (res *Mod, err error) {
-	if user.OAuth2Token() == "" {
-		return res, errors.New("requires OAuth2 token")
+func (user *User) EditMod(modID int, gameID int, options *EditModOptions) (res *Mod, err error) {
+	return user.editMod(modID, gameID, options, "")
+}
+
+// EditModLocalized submits a translation of the mod's name, summary and
+// description in language. Games must have localization enabled on mod.io
+func (user *User) EditModLocalized(modID int, gameID int, language string, options *LocalizedModOptions) (res *Mod, err error) {
+	if language == "" {
+		return nil, errors.New("language is required")
+	}
+	if options == nil {
+		return nil, errOptionsRequired
+	}
+	return user.editMod(modID, gameID, &EditModOptions{
+		Name:        options.Name,
+		Summary:     options.Summary,
+		Description: options.Description,
+	}, language)
+}
+
+func (user *User) editMod(modID int, gameID int, options *EditModOptions, contentLanguage string) (res *Mod, err error) {
+	err = user.requireToken()
+	if err != nil {
+		return res, err
+	}
+	err = options.Validate()
+	if err != nil {
+		return res, err
 	}
-	reqBody := ParseArgsBody(options)
+	reqBody := options.values()
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("PUT", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID), strings.NewReader(reqBody.Encode()))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequest("PUT", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID), strings.NewReader(reqBody.Encode()))
 	if err != nil {
 		return res, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
+	if contentLanguage != "" {
+		req.Header.Set("Content-Language", contentLanguage)
 	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return res, err
 	}
@@ -181,7 +157,7 @@ func (user *User) EditMod(modID int, gameID int, options map[string]string) (res
 		var errObj ErrorCase
 		e := json.Unmarshal(b, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return nil, e
 		}
 		return nil, HandleResponseError(errObj)
 	}
@@ -197,16 +173,16 @@ func (user *User) EditMod(modID int, gameID int, options map[string]string) (res
 
 // DeleteMod sends a request to delete a mod
 func (user *User) DeleteMod(modID int, gameID int) (err error) {
-	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("DELETE", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID), nil)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	err = user.requireToken()
 	if err != nil {
 		return err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
+	client := http.Client{Timeout: time.Duration(5 * time.Second)}
+	req, err := user.newRequest("DELETE", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID), nil)
+	if err != nil {
+		return err
 	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return err
 	}
@@ -220,47 +196,44 @@ func (user *User) DeleteMod(modID int, gameID int) (err error) {
 		var errObj ErrorCase
 		e := json.Unmarshal(body, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return e
 		}
 		return HandleResponseError(errObj)
 	}
 	return nil
 }
 
-// AddMod adds a mod taking bytes for files and returns Mod object
-func (user *User) AddMod(logo string, modName string, summary string, options map[string]string, gameID int) (res *Mod, err error) {
-	if user.OAuth2Token() == "" {
-		return res, errors.New("requires OAuth2 token")
+// AddMod adds a mod uploading the logo in options and returns Mod object
+func (user *User) AddMod(options *AddModOptions, gameID int) (res *Mod, err error) {
+	err = user.requireToken()
+	if err != nil {
+		return res, err
 	}
-	file, err := os.Open(logo)
+	err = options.Validate()
 	if err != nil {
 		return res, err
 	}
-	defer file.Close()
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
-	part, err := writer.CreateFormFile("logo", filepath.Base(logo))
+	err = writeFile(writer, "logo", options.Logo)
 	if err != nil {
 		return res, err
 	}
-	_, err = io.Copy(part, file)
-	_ = writer.WriteField("name", modName)
-	_ = writer.WriteField("summary", summary)
-	if options != nil {
-		for k, v := range options {
-			_ = writer.WriteField(k, v)
-		}
+	err = writeFields(writer, options.values())
+	if err != nil {
+		return res, err
 	}
-	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods", body)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	err = writer.Close()
 	if err != nil {
 		return res, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
+	client := http.Client{Timeout: time.Duration(5 * time.Second)}
+	req, err := user.newRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods", body)
+	if err != nil {
+		return res, err
 	}
-	resp, err := client.Do(req)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return res, err
 	}
@@ -273,7 +246,7 @@ func (user *User) AddMod(logo string, modName string, summary string, options ma
 		var errObj ErrorCase
 		e := json.Unmarshal(b, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return nil, e
 		}
 		return nil, HandleResponseError(errObj)
 	}
@@ -284,8 +257,8 @@ func (user *User) AddMod(logo string, modName string, summary string, options ma
 	return res, nil
 }
 
-// GetMod searches for a mod and returns a Mods object
-func (user *User) GetMod(modID int, gameID int, query map[string]string) (res *Mods, err error) {
+// GetMod grabs a single mod and returns a Mod object
+func (user *User) GetMod(modID int, gameID int, query map[string]string) (res *Mod, err error) {
 	var queryString string
 	if query != nil {
 		query["api_key"] = user.APIKey()
@@ -294,15 +267,11 @@ func (user *User) GetMod(modID int, gameID int, query map[string]string) (res *M
 		queryString = "api_key=" + user.APIKey()
 	}
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(gameID)+"?"+queryString, nil)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"?"+queryString, nil)
 	if err != nil {
 		return res, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return res, err
 	}
@@ -312,7 +281,7 @@ func (user *User) GetMod(modID int, gameID int, query map[string]string) (res *M
 		var errObj ErrorCase
 		e := json.Unmarshal(body, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return nil, e
 		}
 		return nil, HandleResponseError(errObj)
 	}
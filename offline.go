@@ -0,0 +1,593 @@
+package gomodio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotCached is returned in offline mode when the requested object was never synced
+var ErrNotCached = errors.New("not available offline")
+
+// Offline write operations
+const (
+	OpSubscribe   = "subscribe"
+	OpUnsubscribe = "unsubscribe"
+	OpRating      = "rating"
+)
+
+// QueuedWrite is a write made while offline, replayed when connectivity returns
+type QueuedWrite struct {
+	Op     string    `json:"op"`
+	GameID int       `json:"game_id"`
+	ModID  int       `json:"mod_id"`
+	Rating Rating    `json:"rating,omitempty"`
+	Date   Timestamp `json:"date"`
+}
+
+// offlineCatalogue is what an OfflineStore persists
+type offlineCatalogue struct {
+	Games         map[int]Game       `json:"games"`
+	Mods          map[int]Mod        `json:"mods"`
+	Tags          map[int]TagOptions `json:"tags"`
+	Modfiles      map[int][]File     `json:"modfiles"`
+	Subscriptions map[int]bool       `json:"subscriptions"`
+	Queue         []QueuedWrite      `json:"queue"`
+	DateSynced    Timestamp          `json:"date_synced"`
+}
+
+// OfflineStore persists the last known games, mods, tags, modfiles and subscriptions
+// to a JSON file, along with writes waiting to be replayed
+type OfflineStore struct {
+	mu   sync.Mutex
+	path string
+	cat  offlineCatalogue
+}
+
+// OpenOfflineStore loads the store at path, starting empty when the file does not exist
+func OpenOfflineStore(path string) (*OfflineStore, error) {
+	s := &OfflineStore{path: path}
+	b, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(b) > 0 {
+		err = json.Unmarshal(b, &s.cat)
+		if err != nil {
+			return nil, err
+		}
+	}
+	s.init()
+	return s, nil
+}
+
+func (s *OfflineStore) init() {
+	if s.cat.Games == nil {
+		s.cat.Games = map[int]Game{}
+	}
+	if s.cat.Mods == nil {
+		s.cat.Mods = map[int]Mod{}
+	}
+	if s.cat.Tags == nil {
+		s.cat.Tags = map[int]TagOptions{}
+	}
+	if s.cat.Modfiles == nil {
+		s.cat.Modfiles = map[int][]File{}
+	}
+	if s.cat.Subscriptions == nil {
+		s.cat.Subscriptions = map[int]bool{}
+	}
+}
+
+// DateSynced returns when the store was last synced with mod.io
+func (s *OfflineStore) DateSynced() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cat.DateSynced.Time()
+}
+
+// Subscribed reports whether the user was last known to be subscribed to the mod
+func (s *OfflineStore) Subscribed(modID int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cat.Subscriptions[modID]
+}
+
+// Queue returns the writes waiting to be replayed
+func (s *OfflineStore) Queue() []QueuedWrite {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]QueuedWrite(nil), s.cat.Queue...)
+}
+
+// Modfiles returns the synced modfiles of a mod
+func (s *OfflineStore) Modfiles(modID int) []File {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]File(nil), s.cat.Modfiles[modID]...)
+}
+
+// update changes the catalogue with fn and saves it
+func (s *OfflineStore) update(fn func(c *offlineCatalogue)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&s.cat)
+	return s.save()
+}
+
+func (s *OfflineStore) save() error {
+	b, err := json.Marshal(&s.cat)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), ".offline-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(b)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// Sync downloads a game, its mods and tag options into the store. When the User has
+// a token, subscriptions and the modfiles of subscribed mods are synced too
+func (s *OfflineStore) Sync(user *User, gameID int) error {
+	game, err := user.GetGame(gameID, nil)
+	if err != nil {
+		return err
+	}
+	tags, err := user.GetGameTagOptions(gameID)
+	if err != nil {
+		return err
+	}
+	var mods []Mod
+	for offset := 0; ; offset += modPageSize {
+		page, err := user.GetMods(gameID, map[string]string{
+			"_limit":  strconv.Itoa(modPageSize),
+			"_offset": strconv.Itoa(offset),
+		})
+		if err != nil {
+			return err
+		}
+		mods = append(mods, page.Data...)
+		if len(page.Data) == 0 || offset+len(page.Data) >= page.ResultTotal {
+			break
+		}
+	}
+	var subscribed []Mod
+	modfiles := map[int][]File{}
+	if user.OAuth2Token() != "" {
+		for offset := 0; ; offset += modPageSize {
+			page, err := user.GetSubscriptions(map[string]string{
+				"game_id": strconv.Itoa(gameID),
+				"_limit":  strconv.Itoa(modPageSize),
+				"_offset": strconv.Itoa(offset),
+			})
+			if err != nil {
+				return err
+			}
+			subscribed = append(subscribed, page.Data...)
+			if len(page.Data) == 0 || offset+len(page.Data) >= page.ResultTotal {
+				break
+			}
+		}
+		for _, m := range subscribed {
+			files, err := GetModfiles(m.ID, gameID, nil, user)
+			if err != nil {
+				return err
+			}
+			modfiles[m.ID] = files.Data
+		}
+	}
+	return s.update(func(c *offlineCatalogue) {
+		c.Games[gameID] = *game
+		c.Tags[gameID] = *tags
+		// Subscriptions are matched to the game through the mods synced before
+		if user.OAuth2Token() != "" {
+			for id := range c.Subscriptions {
+				if c.Mods[id].GameID == gameID {
+					delete(c.Subscriptions, id)
+				}
+			}
+		}
+		for id, m := range c.Mods {
+			if m.GameID == gameID {
+				delete(c.Mods, id)
+			}
+		}
+		for _, m := range mods {
+			c.Mods[m.ID] = m
+		}
+		if user.OAuth2Token() != "" {
+			for _, m := range subscribed {
+				c.Mods[m.ID] = m
+				c.Subscriptions[m.ID] = true
+			}
+			for id, files := range modfiles {
+				c.Modfiles[id] = files
+			}
+			// Writes still queued were made after the server state was read
+			for _, w := range c.Queue {
+				applyQueuedWrite(c, w)
+			}
+		}
+		c.DateSynced = NewTimestamp(time.Now())
+	})
+}
+
+// isNetworkError reports whether err means mod.io could not be reached, as opposed to an
+// API error or the caller cancelling the request
+func isNetworkError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr)
+}
+
+// OfflineClient serves reads from mod.io, falling back to an OfflineStore when the
+// network is unreachable. Results served from the store are reported as stale
+type OfflineClient struct {
+	user  *User
+	store *OfflineStore
+}
+
+// NewOfflineClient returns a client reading through user and keeping store up to date
+func NewOfflineClient(user *User, store *OfflineStore) *OfflineClient {
+	return &OfflineClient{user: user, store: store}
+}
+
+// GetGame gets a game, from the store when offline
+func (c *OfflineClient) GetGame(gameID int) (res *Game, stale bool, err error) {
+	res, err = c.user.GetGame(gameID, nil)
+	if err == nil {
+		g := *res
+		return res, false, c.store.update(func(cat *offlineCatalogue) { cat.Games[gameID] = g })
+	}
+	if !isNetworkError(err) {
+		return nil, false, err
+	}
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	g, ok := c.store.cat.Games[gameID]
+	if !ok {
+		return nil, true, fmt.Errorf("game %d %w: %v", gameID, ErrNotCached, err)
+	}
+	return &g, true, nil
+}
+
+// GetMod gets a mod, from the store when offline
+func (c *OfflineClient) GetMod(modID, gameID int) (res *Mod, stale bool, err error) {
+	res, err = c.user.GetMod(modID, gameID, nil)
+	if err == nil {
+		m := *res
+		return res, false, c.store.update(func(cat *offlineCatalogue) { cat.Mods[modID] = m })
+	}
+	if !isNetworkError(err) {
+		return nil, false, err
+	}
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	m, ok := c.store.cat.Mods[modID]
+	if !ok || m.GameID != gameID {
+		return nil, true, fmt.Errorf("mod %d %w: %v", modID, ErrNotCached, err)
+	}
+	return &m, true, nil
+}
+
+// GetGameTagOptions gets a game's tag options, from the store when offline
+func (c *OfflineClient) GetGameTagOptions(gameID int) (res *TagOptions, stale bool, err error) {
+	res, err = c.user.GetGameTagOptions(gameID)
+	if err == nil {
+		t := *res
+		return res, false, c.store.update(func(cat *offlineCatalogue) { cat.Tags[gameID] = t })
+	}
+	if !isNetworkError(err) {
+		return nil, false, err
+	}
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	t, ok := c.store.cat.Tags[gameID]
+	if !ok {
+		return nil, true, fmt.Errorf("tags of game %d %w: %v", gameID, ErrNotCached, err)
+	}
+	return &t, true, nil
+}
+
+// GetMods searches a game's mods, from the store when offline. Offline, the filters
+// id, name, name-lk (with * wildcards), submitted_by, tags and tags-in are supported,
+// along with _sort on id, name, date_added, date_updated, date_live, downloads_total,
+// subscribers_total, popular and rating, and _limit and _offset. Any other filter
+// fails offline rather than being ignored
+func (c *OfflineClient) GetMods(gameID int, query map[string]string) (res *Mods, stale bool, err error) {
+	q := map[string]string{}
+	for k, v := range query {
+		q[k] = v
+	}
+	res, err = c.user.GetMods(gameID, q)
+	if err == nil {
+		mods := res.Data
+		return res, false, c.store.update(func(cat *offlineCatalogue) {
+			for _, m := range mods {
+				cat.Mods[m.ID] = m
+			}
+		})
+	}
+	if !isNetworkError(err) {
+		return nil, false, err
+	}
+	if k := unsupportedModQuery(query); k != "" {
+		return nil, true, fmt.Errorf("mods filtered by %s %w: %v", k, ErrNotCached, err)
+	}
+	c.store.mu.Lock()
+	var mods []Mod
+	for _, m := range c.store.cat.Mods {
+		if m.GameID == gameID && matchModQuery(&m, query) {
+			mods = append(mods, m)
+		}
+	}
+	c.store.mu.Unlock()
+	sortMods(mods, query["_sort"])
+	total := len(mods)
+	offset, _ := strconv.Atoi(query["_offset"])
+	limit, _ := strconv.Atoi(query["_limit"])
+	if limit <= 0 {
+		limit = modPageSize
+	}
+	if offset > len(mods) {
+		offset = len(mods)
+	}
+	mods = mods[offset:]
+	if len(mods) > limit {
+		mods = mods[:limit]
+	}
+	return &Mods{
+		Data:         mods,
+		ResultCount:  len(mods),
+		ResultLimit:  limit,
+		ResultOffset: offset,
+		ResultTotal:  total,
+	}, true, nil
+}
+
+// offlineModSorts are the _sort fields sortMods supports
+var offlineModSorts = map[string]bool{
+	"id": true, "name": true, "date_added": true, "date_updated": true, "date_live": true,
+	"downloads_total": true, "subscribers_total": true, "popular": true, "rating": true,
+}
+
+// unsupportedModQuery returns the first filter in query that cannot be applied offline, or ""
+func unsupportedModQuery(query map[string]string) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		switch k {
+		case "id", "name", "name-lk", "submitted_by", "tags", "tags-in", "_limit", "_offset":
+		case "_sort":
+			if query[k] != "" && !offlineModSorts[strings.TrimPrefix(query[k], "-")] {
+				return k + "=" + query[k]
+			}
+		default:
+			return k
+		}
+	}
+	return ""
+}
+
+func matchModQuery(m *Mod, query map[string]string) bool {
+	for k, v := range query {
+		switch k {
+		case "id":
+			if strconv.Itoa(m.ID) != v {
+				return false
+			}
+		case "name":
+			if !strings.EqualFold(m.Name, v) {
+				return false
+			}
+		case "name-lk":
+			if !likeMatch(strings.ToLower(m.Name), strings.ToLower(v)) {
+				return false
+			}
+		case "submitted_by":
+			if strconv.Itoa(m.SubmittedBy.ID) != v {
+				return false
+			}
+		case "tags", "tags-in":
+			has := map[string]bool{}
+			for _, t := range m.Tags {
+				has[t.Name] = true
+			}
+			matched := 0
+			wanted := strings.Split(v, ",")
+			for _, t := range wanted {
+				if has[t] {
+					matched++
+				}
+			}
+			if (k == "tags" && matched < len(wanted)) || (k == "tags-in" && matched == 0) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// likeMatch matches s against a pattern where * matches any run of characters
+func likeMatch(s, pattern string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return s == pattern
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	for _, p := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, p)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(p):]
+	}
+	return strings.HasSuffix(s, parts[len(parts)-1])
+}
+
+func sortMods(mods []Mod, field string) {
+	desc := strings.HasPrefix(field, "-")
+	field = strings.TrimPrefix(field, "-")
+	less := func(a, b *Mod) bool { return a.ID < b.ID }
+	switch field {
+	case "name":
+		less = func(a, b *Mod) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	case "date_added":
+		less = func(a, b *Mod) bool { return a.DateAdded < b.DateAdded }
+	case "date_updated":
+		less = func(a, b *Mod) bool { return a.DateUpdated < b.DateUpdated }
+	case "date_live":
+		less = func(a, b *Mod) bool { return a.DateLive < b.DateLive }
+	case "downloads_total":
+		less = func(a, b *Mod) bool { return a.Stats.DownloadsTotal < b.Stats.DownloadsTotal }
+	case "subscribers_total":
+		less = func(a, b *Mod) bool { return a.Stats.SubscribersTotal < b.Stats.SubscribersTotal }
+	case "popular":
+		// A lower rank position is more popular
+		less = func(a, b *Mod) bool { return a.Stats.PopularityRankPosition > b.Stats.PopularityRankPosition }
+	case "rating":
+		less = func(a, b *Mod) bool { return a.Stats.RatingsWeightedAggregate < b.Stats.RatingsWeightedAggregate }
+	}
+	sort.SliceStable(mods, func(i, j int) bool {
+		if desc {
+			return less(&mods[j], &mods[i])
+		}
+		return less(&mods[i], &mods[j])
+	})
+}
+
+// Subscribe subscribes to a mod, queueing the subscription when offline
+func (c *OfflineClient) Subscribe(modID, gameID int) (queued bool, err error) {
+	_, err = c.user.SubscribeToMod(modID, gameID)
+	return c.write(QueuedWrite{Op: OpSubscribe, GameID: gameID, ModID: modID}, err)
+}
+
+// Unsubscribe unsubscribes from a mod, queueing the change when offline
+func (c *OfflineClient) Unsubscribe(modID, gameID int) (queued bool, err error) {
+	err = c.user.UnsubscribeToMod(modID, gameID)
+	return c.write(QueuedWrite{Op: OpUnsubscribe, GameID: gameID, ModID: modID}, err)
+}
+
+// SetModRating rates a mod, queueing the rating when offline
+func (c *OfflineClient) SetModRating(rating Rating, modID, gameID int) (queued bool, err error) {
+	_, err = c.user.SetModRating(rating, modID, gameID)
+	return c.write(QueuedWrite{Op: OpRating, GameID: gameID, ModID: modID, Rating: rating}, err)
+}
+
+// write records the outcome of a write, queueing it when it failed for lack of network
+func (c *OfflineClient) write(w QueuedWrite, err error) (bool, error) {
+	if err != nil && !isNetworkError(err) {
+		return false, err
+	}
+	queued := err != nil
+	return queued, c.store.update(func(cat *offlineCatalogue) {
+		applyQueuedWrite(cat, w)
+		// A later write to the same mod supersedes an earlier one of the same kind
+		kept := cat.Queue[:0]
+		for _, q := range cat.Queue {
+			if q.ModID == w.ModID && sameWriteKind(q.Op, w.Op) {
+				continue
+			}
+			kept = append(kept, q)
+		}
+		cat.Queue = kept
+		if queued {
+			w.Date = NewTimestamp(time.Now())
+			cat.Queue = append(cat.Queue, w)
+		}
+	})
+}
+
+func sameWriteKind(a, b string) bool {
+	if a == OpRating || b == OpRating {
+		return a == b
+	}
+	return true
+}
+
+// applyQueuedWrite updates the local subscription state for a write
+func applyQueuedWrite(cat *offlineCatalogue, w QueuedWrite) {
+	switch w.Op {
+	case OpSubscribe:
+		cat.Subscriptions[w.ModID] = true
+	case OpUnsubscribe:
+		delete(cat.Subscriptions, w.ModID)
+	}
+}
+
+// Replay sends the queued writes in order. It stops, keeping the rest queued, at the
+// first network error. Writes mod.io rejects are dropped and their errors returned
+func (c *OfflineClient) Replay() (replayed int, err error) {
+	var rejected []string
+	for _, w := range c.store.Queue() {
+		var e error
+		switch w.Op {
+		case OpSubscribe:
+			_, e = c.user.SubscribeToMod(w.ModID, w.GameID)
+		case OpUnsubscribe:
+			e = c.user.UnsubscribeToMod(w.ModID, w.GameID)
+		case OpRating:
+			_, e = c.user.SetModRating(w.Rating, w.ModID, w.GameID)
+		default:
+			e = fmt.Errorf("unknown operation %q", w.Op)
+		}
+		if e != nil && isNetworkError(e) {
+			err = e
+			break
+		}
+		if e != nil {
+			rejected = append(rejected, fmt.Sprintf("%s mod %d: %v", w.Op, w.ModID, e))
+		} else {
+			replayed++
+		}
+		// Writes made during the replay may have changed the queue, so w is removed by value
+		w := w
+		uerr := c.store.update(func(cat *offlineCatalogue) {
+			for i, q := range cat.Queue {
+				if q == w {
+					cat.Queue = append(cat.Queue[:i], cat.Queue[i+1:]...)
+					break
+				}
+			}
+		})
+		if uerr != nil {
+			return replayed, uerr
+		}
+	}
+	if err != nil {
+		return replayed, err
+	}
+	if len(rejected) > 0 {
+		return replayed, errors.New("rejected queued writes: " + strings.Join(rejected, "; "))
+	}
+	return replayed, nil
+}
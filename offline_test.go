@@ -0,0 +1,315 @@
+package gomodio
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func offlineMods() []Mod {
+	mods := []Mod{
+		{ID: 1, GameID: 1, Name: "Big Map", Tags: []ModTag{{Name: "Maps"}, {Name: "Large"}}},
+		{ID: 2, GameID: 1, Name: "Small Map", Tags: []ModTag{{Name: "Maps"}}},
+		{ID: 3, GameID: 1, Name: "texture pack", Tags: []ModTag{{Name: "Textures"}}},
+		{ID: 4, GameID: 2, Name: "Other Game Map"},
+	}
+	mods[0].SubmittedBy.ID = 10
+	mods[0].Stats.DownloadsTotal = 50
+	mods[1].Stats.DownloadsTotal = 200
+	mods[2].Stats.DownloadsTotal = 100
+	mods[0].Stats.PopularityRankPosition = 3
+	mods[1].Stats.PopularityRankPosition = 1
+	mods[2].Stats.PopularityRankPosition = 2
+	return mods
+}
+
+func TestLikeMatch(t *testing.T) {
+	tests := []struct {
+		s, pattern string
+		want       bool
+	}{
+		{"big map", "big map", true},
+		{"big map", "big", false},
+		{"big map", "big*", true},
+		{"big map", "*map", true},
+		{"big map", "*g m*", true},
+		{"big map", "b*m*p", true},
+		{"big map", "b*x*p", false},
+		{"big map", "*", true},
+		{"map", "map*map", false},
+	}
+	for _, tt := range tests {
+		if got := likeMatch(tt.s, tt.pattern); got != tt.want {
+			t.Errorf("likeMatch(%q, %q) = %v, want %v", tt.s, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestMatchModQuery(t *testing.T) {
+	tests := []struct {
+		query map[string]string
+		want  []int
+	}{
+		{nil, []int{1, 2, 3}},
+		{map[string]string{"id": "2"}, []int{2}},
+		{map[string]string{"name": "big map"}, []int{1}},
+		{map[string]string{"name-lk": "*MAP"}, []int{1, 2}},
+		{map[string]string{"submitted_by": "10"}, []int{1}},
+		{map[string]string{"tags": "Maps,Large"}, []int{1}},
+		{map[string]string{"tags-in": "Large,Textures"}, []int{1, 3}},
+		{map[string]string{"name-lk": "*map", "tags": "Large"}, []int{1}},
+	}
+	for _, tt := range tests {
+		var got []int
+		for _, m := range offlineMods()[:3] {
+			if matchModQuery(&m, tt.query) {
+				got = append(got, m.ID)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("matchModQuery(%v) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSortMods(t *testing.T) {
+	tests := []struct {
+		field string
+		want  []int
+	}{
+		{"", []int{1, 2, 3}},
+		{"-id", []int{3, 2, 1}},
+		{"name", []int{1, 2, 3}},
+		{"-name", []int{3, 2, 1}},
+		{"downloads_total", []int{1, 3, 2}},
+		{"-downloads_total", []int{2, 3, 1}},
+		{"popular", []int{1, 3, 2}},
+		{"-popular", []int{2, 3, 1}},
+	}
+	for _, tt := range tests {
+		mods := offlineMods()[:3]
+		sortMods(mods, tt.field)
+		var got []int
+		for _, m := range mods {
+			got = append(got, m.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sortMods(%q) = %v, want %v", tt.field, got, tt.want)
+		}
+	}
+}
+
+// offlineTransport fails every request like an unreachable network, or sends
+// them to a test server once online is set
+type offlineTransport struct {
+	mu     sync.Mutex
+	server *httptest.Server
+	online bool
+}
+
+func (t *offlineTransport) setOnline(online bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.online = online
+}
+
+func (t *offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	online := t.online
+	t.mu.Unlock()
+	if !online {
+		return nil, errors.New("network is unreachable")
+	}
+	u, _ := url.Parse(t.server.URL)
+	req = req.Clone(req.Context())
+	req.URL.Scheme = u.Scheme
+	req.URL.Host = u.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newOfflineTestClient(t *testing.T, handler http.HandlerFunc) (*OfflineClient, *offlineTransport) {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	tr := &offlineTransport{server: srv}
+	user := NewUser("key", "")
+	user.SetOAuth2Token("token")
+	user.transport = tr
+	store, err := OpenOfflineStore(filepath.Join(t.TempDir(), "offline.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewOfflineClient(user, store), tr
+}
+
+func TestOfflineGetModsPaging(t *testing.T) {
+	c, _ := newOfflineTestClient(t, nil)
+	c.store.update(func(cat *offlineCatalogue) {
+		for _, m := range offlineMods() {
+			cat.Mods[m.ID] = m
+		}
+	})
+	res, stale, err := c.GetMods(1, map[string]string{"_sort": "-id", "_limit": "2", "_offset": "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stale {
+		t.Error("offline result not reported as stale")
+	}
+	var got []int
+	for _, m := range res.Data {
+		got = append(got, m.ID)
+	}
+	if !reflect.DeepEqual(got, []int{2, 1}) {
+		t.Errorf("mods = %v, want [2 1]", got)
+	}
+	if res.ResultCount != 2 || res.ResultTotal != 3 || res.ResultLimit != 2 || res.ResultOffset != 1 {
+		t.Errorf("paging = count %d total %d limit %d offset %d", res.ResultCount, res.ResultTotal, res.ResultLimit, res.ResultOffset)
+	}
+	res, _, err = c.GetMods(1, map[string]string{"_offset": "10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Data) != 0 || res.ResultTotal != 3 {
+		t.Errorf("past the end: %d mods, total %d", len(res.Data), res.ResultTotal)
+	}
+}
+
+func TestOfflineWriteAndReplay(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	var c *OfflineClient
+	c, tr := newOfflineTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		if r.URL.Path == "/v1/games/1/mods/1/subscribe" {
+			// The user unsubscribes offline while the subscription is being replayed
+			c.write(QueuedWrite{Op: OpUnsubscribe, GameID: 1, ModID: 1}, &url.Error{Op: "Delete", Err: errors.New("offline")})
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1,"code":201,"message":"ok"}`))
+	})
+
+	queued, err := c.Subscribe(1, 1)
+	if err != nil || !queued {
+		t.Fatalf("Subscribe = %v, %v, want queued", queued, err)
+	}
+	if !c.store.Subscribed(1) {
+		t.Error("queued subscription not applied locally")
+	}
+	queued, err = c.SetModRating(RatingPositive, 2, 1)
+	if err != nil || !queued {
+		t.Fatalf("SetModRating = %v, %v, want queued", queued, err)
+	}
+	if n := len(c.store.Queue()); n != 2 {
+		t.Fatalf("queue length = %d, want 2", n)
+	}
+
+	tr.setOnline(true)
+	replayed, err := c.Replay()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed != 2 {
+		t.Errorf("replayed = %d, want 2", replayed)
+	}
+	want := []string{"POST /v1/games/1/mods/1/subscribe", "POST /v1/games/1/mods/2/ratings"}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+	// Only the unsubscribe queued during the replay is left
+	queue := c.store.Queue()
+	if len(queue) != 1 || queue[0].Op != OpUnsubscribe || queue[0].ModID != 1 {
+		t.Errorf("queue = %+v, want the unsubscribe of mod 1", queue)
+	}
+}
+
+func TestOfflineGetModsUnsupportedFilter(t *testing.T) {
+	c, _ := newOfflineTestClient(t, nil)
+	c.store.update(func(cat *offlineCatalogue) {
+		for _, m := range offlineMods() {
+			cat.Mods[m.ID] = m
+		}
+	})
+	tests := []struct {
+		query map[string]string
+		bad   string
+	}{
+		{map[string]string{"name-lk": "*map", "_sort": "-popular", "_limit": "5"}, ""},
+		{map[string]string{"_sort": ""}, ""},
+		{map[string]string{"name-lk": "*map", "metadata_blob-lk": "*x*"}, "metadata_blob-lk"},
+		{map[string]string{"_sort": "-date_ranked"}, "_sort=-date_ranked"},
+	}
+	for _, tt := range tests {
+		_, stale, err := c.GetMods(1, tt.query)
+		if tt.bad == "" {
+			if err != nil {
+				t.Errorf("GetMods(%v) = %v", tt.query, err)
+			}
+			continue
+		}
+		if !stale || !errors.Is(err, ErrNotCached) || !strings.Contains(err.Error(), tt.bad) {
+			t.Errorf("GetMods(%v) = %v, stale %v; want a not cached error naming %s", tt.query, err, stale, tt.bad)
+		}
+	}
+}
+
+func TestIsNetworkError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&url.Error{Op: "Get", Err: errors.New("connection refused")}, true},
+		{&net.OpError{Op: "dial", Err: errors.New("no route to host")}, true},
+		{&url.Error{Op: "Get", Err: context.Canceled}, false},
+		{&url.Error{Op: "Get", Err: context.DeadlineExceeded}, false},
+		{ErrTokenExpired, false},
+	}
+	for _, tt := range tests {
+		if got := isNetworkError(tt.err); got != tt.want {
+			t.Errorf("isNetworkError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestOfflineSyncClearsRemovedSubscriptions(t *testing.T) {
+	c, tr := newOfflineTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/games/1":
+			w.Write([]byte(`{"id":1,"name":"Game"}`))
+		case "/v1/games/1/tags":
+			w.Write([]byte(`{"data":[]}`))
+		case "/v1/games/1/mods":
+			w.Write([]byte(`{"data":[{"id":1,"game_id":1,"name":"Big Map"}],"result_total":1}`))
+		case "/v1/me/subscribed":
+			w.Write([]byte(`{"data":[],"result_total":0}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	tr.setOnline(true)
+	c.store.update(func(cat *offlineCatalogue) {
+		for _, m := range offlineMods() {
+			cat.Mods[m.ID] = m
+			cat.Subscriptions[m.ID] = true
+		}
+	})
+	if err := c.store.Sync(c.user, 1); err != nil {
+		t.Fatal(err)
+	}
+	// Mods 2 and 3 were removed from game 1, mod 4 belongs to another game
+	for id, want := range map[int]bool{1: false, 2: false, 3: false, 4: true} {
+		if got := c.store.Subscribed(id); got != want {
+			t.Errorf("Subscribed(%d) = %v, want %v", id, got, want)
+		}
+	}
+}
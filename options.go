@@ -0,0 +1,509 @@
+package gomodio
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Field limits enforced by mod.io
+const (
+	maxNameLength         = 50
+	maxNameIDLength       = 80
+	maxSummaryLength      = 250
+	maxDescriptionLength  = 50000
+	maxVersionLength      = 50
+	maxChangelogLength    = 50000
+	maxMetadataBlobLength = 50000
+	maxCommentLength      = 10000
+)
+
+// errOptionsRequired is returned by Validate when the options are nil
+var errOptionsRequired = errors.New("options are required")
+
+// String returns a pointer to s, for setting optional string fields
+func String(s string) *string {
+	return &s
+}
+
+// Int returns a pointer to i, for setting optional int fields
+func Int(i int) *int {
+	return &i
+}
+
+// Bool returns a pointer to b, for setting optional bool fields
+func Bool(b bool) *bool {
+	return &b
+}
+
+// AddModOptions are the fields of an Add Mod request. Logo, Name and Summary are required
+type AddModOptions struct {
+	Logo           string
+	Name           string
+	Summary        string
+	NameID         *string
+	Description    *string
+	HomepageURL    *string
+	Visible        *Visibility
+	MaturityOption *MaturityOption
+	MetadataBlob   *string
+	Tags           []string
+}
+
+// EditModOptions are the fields of an Edit Mod request. Nil fields are left unchanged
+type EditModOptions struct {
+	Name           *string
+	NameID         *string
+	Summary        *string
+	Description    *string
+	HomepageURL    *string
+	Visible        *Visibility
+	MaturityOption *MaturityOption
+	MetadataBlob   *string
+	Tags           []string
+	// Modfile is the ID of the file to make the mod's primary modfile
+	Modfile *int
+}
+
+// LocalizedModOptions are the translatable fields of a mod. Nil fields are left unchanged
+type LocalizedModOptions struct {
+	Name        *string
+	Summary     *string
+	Description *string
+}
+
+// EditGameOptions are the fields of an Edit Game request. Nil fields are left unchanged
+type EditGameOptions struct {
+	Name               *string
+	NameID             *string
+	Summary            *string
+	Instructions       *string
+	InstructionsURL    *string
+	UgcName            *string
+	PresentationOption *int
+	SubmissionOption   *int
+	CurationOption     *int
+	CommunityOptions   *CommunityOptions
+	RevenueOptions     *int
+	APIAccessOptions   *APIAccessOptions
+	MaturityOptions    *GameMaturityOption
+}
+
+// AddModfileOptions are the fields of an Add Modfile request. Filedata is the path of the file to upload
+type AddModfileOptions struct {
+	Filedata     string
+	Version      *string
+	Changelog    *string
+	Active       *bool
+	Filehash     *string
+	MetadataBlob *string
+	// Platforms the file is for. Empty targets all of the game's platforms
+	Platforms []Platform
+}
+
+// EditModfileOptions are the fields of an Edit Modfile request. Nil fields are left unchanged
+type EditModfileOptions struct {
+	Version      *string
+	Changelog    *string
+	Active       *bool
+	MetadataBlob *string
+}
+
+// AddModCommentOptions are the optional fields of an Add Mod Comment request
+type AddModCommentOptions struct {
+	ReplyID *int
+}
+
+// AddModMediaOptions are the fields of an Add Mod Media request.
+// Logo, ImagesZip and Images are paths of files to upload
+type AddModMediaOptions struct {
+	Logo      string
+	ImagesZip string
+	Images    []string
+	Youtube   []string
+	Sketchfab []string
+}
+
+// DeleteModMediaOptions are the media to remove in a Delete Mod Media request.
+// Images are filenames of the mod's images, Youtube and Sketchfab are links
+type DeleteModMediaOptions struct {
+	Images    []string
+	Youtube   []string
+	Sketchfab []string
+}
+
+// ModStatsFilter filters and sorts Get Mods Stats. Zero fields are not sent
+type ModStatsFilter struct {
+	// ModIDs limits results to the given mods (mod_id-in)
+	ModIDs []int
+	// Bounds on popularity_rank_position. Position 1 is the most popular mod
+	RankMin *int
+	RankMax *int
+	// Bounds on downloads_total
+	DownloadsMin *int
+	DownloadsMax *int
+	// Bounds on subscribers_total
+	SubscribersMin *int
+	SubscribersMax *int
+	// Sort is the field to sort by, prefixed with - for descending, e.g. "-downloads_total"
+	Sort   string
+	Limit  int
+	Offset int
+}
+
+// Validate checks the options against mod.io's field limits
+func (o *AddModOptions) Validate() error {
+	if o == nil {
+		return errOptionsRequired
+	}
+	if o.Logo == "" {
+		return errors.New("logo is required")
+	}
+	if o.Name == "" {
+		return errors.New("name is required")
+	}
+	if o.Summary == "" {
+		return errors.New("summary is required")
+	}
+	return validateFields(
+		checkLength("name", &o.Name, maxNameLength),
+		checkLength("name_id", o.NameID, maxNameIDLength),
+		checkLength("summary", &o.Summary, maxSummaryLength),
+		checkLength("description", o.Description, maxDescriptionLength),
+		checkLength("metadata_blob", o.MetadataBlob, maxMetadataBlobLength),
+		checkURL("homepage_url", o.HomepageURL),
+	)
+}
+
+func (o *AddModOptions) values() url.Values {
+	v := url.Values{}
+	v.Set("name", o.Name)
+	v.Set("summary", o.Summary)
+	setString(v, "name_id", o.NameID)
+	setString(v, "description", o.Description)
+	setString(v, "homepage_url", o.HomepageURL)
+	if o.Visible != nil {
+		v.Set("visible", strconv.Itoa(int(*o.Visible)))
+	}
+	if o.MaturityOption != nil {
+		v.Set("maturity_option", strconv.Itoa(int(*o.MaturityOption)))
+	}
+	setString(v, "metadata_blob", o.MetadataBlob)
+	for _, t := range o.Tags {
+		v.Add("tags[]", t)
+	}
+	return v
+}
+
+// Validate checks the options against mod.io's field limits
+func (o *EditModOptions) Validate() error {
+	if o == nil {
+		return errOptionsRequired
+	}
+	return validateFields(
+		checkLength("name", o.Name, maxNameLength),
+		checkLength("name_id", o.NameID, maxNameIDLength),
+		checkLength("summary", o.Summary, maxSummaryLength),
+		checkLength("description", o.Description, maxDescriptionLength),
+		checkLength("metadata_blob", o.MetadataBlob, maxMetadataBlobLength),
+		checkURL("homepage_url", o.HomepageURL),
+	)
+}
+
+func (o *EditModOptions) values() url.Values {
+	v := url.Values{}
+	setString(v, "name", o.Name)
+	setString(v, "name_id", o.NameID)
+	setString(v, "summary", o.Summary)
+	setString(v, "description", o.Description)
+	setString(v, "homepage_url", o.HomepageURL)
+	if o.Visible != nil {
+		v.Set("visible", strconv.Itoa(int(*o.Visible)))
+	}
+	if o.MaturityOption != nil {
+		v.Set("maturity_option", strconv.Itoa(int(*o.MaturityOption)))
+	}
+	setString(v, "metadata_blob", o.MetadataBlob)
+	for _, t := range o.Tags {
+		v.Add("tags[]", t)
+	}
+	setInt(v, "modfile", o.Modfile)
+	return v
+}
+
+// Validate checks the options against mod.io's field limits
+func (o *EditGameOptions) Validate() error {
+	if o == nil {
+		return errOptionsRequired
+	}
+	return validateFields(
+		checkLength("name", o.Name, maxNameLength),
+		checkLength("name_id", o.NameID, maxNameIDLength),
+		checkLength("summary", o.Summary, maxSummaryLength),
+		checkLength("instructions", o.Instructions, maxDescriptionLength),
+		checkURL("instructions_url", o.InstructionsURL),
+	)
+}
+
+func (o *EditGameOptions) values() url.Values {
+	v := url.Values{}
+	setString(v, "name", o.Name)
+	setString(v, "name_id", o.NameID)
+	setString(v, "summary", o.Summary)
+	setString(v, "instructions", o.Instructions)
+	setString(v, "instructions_url", o.InstructionsURL)
+	setString(v, "ugc_name", o.UgcName)
+	setInt(v, "presentation_option", o.PresentationOption)
+	setInt(v, "submission_option", o.SubmissionOption)
+	setInt(v, "curation_option", o.CurationOption)
+	if o.CommunityOptions != nil {
+		v.Set("community_options", strconv.Itoa(int(*o.CommunityOptions)))
+	}
+	setInt(v, "revenue_options", o.RevenueOptions)
+	if o.APIAccessOptions != nil {
+		v.Set("api_access_options", strconv.Itoa(int(*o.APIAccessOptions)))
+	}
+	if o.MaturityOptions != nil {
+		v.Set("maturity_options", strconv.Itoa(int(*o.MaturityOptions)))
+	}
+	return v
+}
+
+// Validate checks the options against mod.io's field limits
+func (o *AddModfileOptions) Validate() error {
+	if o == nil {
+		return errOptionsRequired
+	}
+	if o.Filedata == "" {
+		return errors.New("filedata is required")
+	}
+	return validateFields(
+		checkLength("version", o.Version, maxVersionLength),
+		checkLength("changelog", o.Changelog, maxChangelogLength),
+		checkLength("metadata_blob", o.MetadataBlob, maxMetadataBlobLength),
+	)
+}
+
+func (o *AddModfileOptions) values() url.Values {
+	v := url.Values{}
+	setString(v, "version", o.Version)
+	setString(v, "changelog", o.Changelog)
+	setBool(v, "active", o.Active)
+	setString(v, "filehash", o.Filehash)
+	setString(v, "metadata_blob", o.MetadataBlob)
+	for _, p := range o.Platforms {
+		v.Add("platforms[]", string(p))
+	}
+	return v
+}
+
+// Validate checks the options against mod.io's field limits
+func (o *EditModfileOptions) Validate() error {
+	if o == nil {
+		return errOptionsRequired
+	}
+	return validateFields(
+		checkLength("version", o.Version, maxVersionLength),
+		checkLength("changelog", o.Changelog, maxChangelogLength),
+		checkLength("metadata_blob", o.MetadataBlob, maxMetadataBlobLength),
+	)
+}
+
+func (o *EditModfileOptions) values() url.Values {
+	v := url.Values{}
+	setString(v, "version", o.Version)
+	setString(v, "changelog", o.Changelog)
+	setBool(v, "active", o.Active)
+	setString(v, "metadata_blob", o.MetadataBlob)
+	return v
+}
+
+func (o *AddModCommentOptions) values() url.Values {
+	v := url.Values{}
+	setInt(v, "reply_id", o.ReplyID)
+	return v
+}
+
+// Validate checks that the options upload at least one piece of media
+func (o *AddModMediaOptions) Validate() error {
+	if o == nil {
+		return errOptionsRequired
+	}
+	if o.Logo == "" && o.ImagesZip == "" && len(o.Images) == 0 && len(o.Youtube) == 0 && len(o.Sketchfab) == 0 {
+		return errors.New("no media to add")
+	}
+	for _, u := range o.Youtube {
+		err := checkURL("youtube", &u)
+		if err != nil {
+			return err
+		}
+	}
+	for _, u := range o.Sketchfab {
+		err := checkURL("sketchfab", &u)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *AddModMediaOptions) write(w *multipart.Writer) error {
+	if o.Logo != "" {
+		err := writeFile(w, "logo", o.Logo)
+		if err != nil {
+			return err
+		}
+	}
+	if o.ImagesZip != "" {
+		err := writeFile(w, "images", o.ImagesZip)
+		if err != nil {
+			return err
+		}
+	}
+	for i, img := range o.Images {
+		err := writeFile(w, "image"+strconv.Itoa(i+1), img)
+		if err != nil {
+			return err
+		}
+	}
+	v := url.Values{}
+	for _, u := range o.Youtube {
+		v.Add("youtube[]", u)
+	}
+	for _, u := range o.Sketchfab {
+		v.Add("sketchfab[]", u)
+	}
+	return writeFields(w, v)
+}
+
+// Validate checks that there is media to delete
+func (o *DeleteModMediaOptions) Validate() error {
+	if o == nil {
+		return errOptionsRequired
+	}
+	if len(o.Images) == 0 && len(o.Youtube) == 0 && len(o.Sketchfab) == 0 {
+		return errors.New("no media to delete")
+	}
+	return nil
+}
+
+func (o *DeleteModMediaOptions) values() url.Values {
+	v := url.Values{}
+	for _, img := range o.Images {
+		v.Add("images[]", img)
+	}
+	for _, u := range o.Youtube {
+		v.Add("youtube[]", u)
+	}
+	for _, u := range o.Sketchfab {
+		v.Add("sketchfab[]", u)
+	}
+	return v
+}
+
+func setString(v url.Values, key string, s *string) {
+	if s != nil {
+		v.Set(key, *s)
+	}
+}
+
+func setInt(v url.Values, key string, i *int) {
+	if i != nil {
+		v.Set(key, strconv.Itoa(*i))
+	}
+}
+
+func setBool(v url.Values, key string, b *bool) {
+	if b != nil {
+		v.Set(key, strconv.FormatBool(*b))
+	}
+}
+
+func checkLength(field string, s *string, max int) error {
+	if s != nil && utf8.RuneCountInString(*s) > max {
+		return fmt.Errorf("%s must be at most %d characters", field, max)
+	}
+	return nil
+}
+
+func checkURL(field string, s *string) error {
+	if s == nil || *s == "" {
+		return nil
+	}
+	u, err := url.ParseRequestURI(*s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s must be a valid http or https URL", field)
+	}
+	return nil
+}
+
+func validateFields(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFields writes every value of v as a multipart form field
+func writeFields(w *multipart.Writer, v url.Values) error {
+	for k, vs := range v {
+		for _, s := range vs {
+			err := w.WriteField(k, s)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeFile copies the file at path into a multipart form file field
+func writeFile(w *multipart.Writer, field, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	part, err := w.CreateFormFile(field, filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, file)
+	return err
+}
+
+func (f *ModStatsFilter) values() url.Values {
+	v := url.Values{}
+	if len(f.ModIDs) > 0 {
+		ids := make([]string, len(f.ModIDs))
+		for i, id := range f.ModIDs {
+			ids[i] = strconv.Itoa(id)
+		}
+		v.Set("mod_id-in", strings.Join(ids, ","))
+	}
+	setInt(v, "popularity_rank_position-min", f.RankMin)
+	setInt(v, "popularity_rank_position-max", f.RankMax)
+	setInt(v, "downloads_total-min", f.DownloadsMin)
+	setInt(v, "downloads_total-max", f.DownloadsMax)
+	setInt(v, "subscribers_total-min", f.SubscribersMin)
+	setInt(v, "subscribers_total-max", f.SubscribersMax)
+	if f.Sort != "" {
+		v.Set("_sort", f.Sort)
+	}
+	if f.Limit > 0 {
+		v.Set("_limit", strconv.Itoa(f.Limit))
+	}
+	if f.Offset > 0 {
+		v.Set("_offset", strconv.Itoa(f.Offset))
+	}
+	return v
+}
@@ -0,0 +1,118 @@
+package gomodio
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestValidateNilOptions(t *testing.T) {
+	validators := map[string]interface{ Validate() error }{
+		"AddModOptions":         (*AddModOptions)(nil),
+		"EditModOptions":        (*EditModOptions)(nil),
+		"EditGameOptions":       (*EditGameOptions)(nil),
+		"AddModfileOptions":     (*AddModfileOptions)(nil),
+		"EditModfileOptions":    (*EditModfileOptions)(nil),
+		"AddModMediaOptions":    (*AddModMediaOptions)(nil),
+		"DeleteModMediaOptions": (*DeleteModMediaOptions)(nil),
+	}
+	for name, v := range validators {
+		if err := v.Validate(); err != errOptionsRequired {
+			t.Errorf("%s.Validate() = %v, want %v", name, err, errOptionsRequired)
+		}
+	}
+}
+
+func TestNilOptionsDoNotPanic(t *testing.T) {
+	user := NewUser("key", "")
+	user.SetOAuth2Token("token")
+	if _, err := user.EditMod(1, 1, nil); err != errOptionsRequired {
+		t.Errorf("EditMod = %v", err)
+	}
+	if _, err := user.EditGame(1, nil); err != errOptionsRequired {
+		t.Errorf("EditGame = %v", err)
+	}
+	if _, err := EditModfile(1, 1, 1, nil, user); err != errOptionsRequired {
+		t.Errorf("EditModfile = %v", err)
+	}
+	if _, err := user.AddModfile(1, 1, nil); err != errOptionsRequired {
+		t.Errorf("AddModfile = %v", err)
+	}
+	if _, err := user.AddModMedia(1, 1, nil); err != errOptionsRequired {
+		t.Errorf("AddModMedia = %v", err)
+	}
+	if err := user.DeleteModMedia(1, 1, nil); err != errOptionsRequired {
+		t.Errorf("DeleteModMedia = %v", err)
+	}
+}
+
+func TestEditModLocalizedNilOptions(t *testing.T) {
+	user := NewUser("key", "")
+	user.SetOAuth2Token("token")
+	if _, err := user.EditModLocalized(1, 1, "ja", nil); err != errOptionsRequired {
+		t.Errorf("EditModLocalized = %v, want %v", err, errOptionsRequired)
+	}
+}
+
+func TestDeleteModMedia(t *testing.T) {
+	var method string
+	var form url.Values
+	user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		// ParseForm ignores DELETE bodies
+		b, _ := ioutil.ReadAll(r.Body)
+		form, _ = url.ParseQuery(string(b))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	user.SetOAuth2Token("token")
+	if err := user.DeleteModMedia(2, 1, &DeleteModMediaOptions{}); err == nil {
+		t.Error("empty options accepted")
+	}
+	err := user.DeleteModMedia(2, 1, &DeleteModMediaOptions{
+		Images:  []string{"a.png", "b.png"},
+		Youtube: []string{"https://youtube.com/watch?v=x"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != "DELETE" {
+		t.Errorf("method = %s, want DELETE", method)
+	}
+	want := url.Values{"images[]": {"a.png", "b.png"}, "youtube[]": {"https://youtube.com/watch?v=x"}}
+	if !reflect.DeepEqual(form, want) {
+		t.Errorf("form = %v, want %v", form, want)
+	}
+}
+
+func TestUndecodableErrorBody(t *testing.T) {
+	user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>Bad Gateway</html>"))
+	})
+	user.SetOAuth2Token("token")
+	calls := map[string]func() error{
+		"DeleteMod": func() error { return user.DeleteMod(2, 1) },
+		"GetMod": func() error {
+			_, err := user.GetMod(2, 1, nil)
+			return err
+		},
+		"EditMod": func() error {
+			_, err := user.EditMod(2, 1, &EditModOptions{Name: String("a")})
+			return err
+		},
+		"DeleteModMedia": func() error {
+			return user.DeleteModMedia(2, 1, &DeleteModMediaOptions{Images: []string{"a.png"}})
+		},
+		"AddModMedia": func() error {
+			_, err := user.AddModMedia(2, 1, &AddModMediaOptions{Youtube: []string{"https://youtube.com/watch?v=x"}})
+			return err
+		},
+	}
+	for name, call := range calls {
+		if err := call(); err == nil {
+			t.Errorf("%s returned no error for a 502 HTML body", name)
+		}
+	}
+}
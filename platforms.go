@@ -0,0 +1,80 @@
+package gomodio
+
+import "strconv"
+
+// Platform is a platform mod.io can target modfiles at
+type Platform string
+
+// Platform values
+const (
+	PlatformWindows     Platform = "windows"
+	PlatformMac         Platform = "mac"
+	PlatformLinux       Platform = "linux"
+	PlatformAndroid     Platform = "android"
+	PlatformIOS         Platform = "ios"
+	PlatformXboxOne     Platform = "xboxone"
+	PlatformXboxSeriesX Platform = "xboxseriesx"
+	PlatformPS4         Platform = "ps4"
+	PlatformPS5         Platform = "ps5"
+	PlatformSwitch      Platform = "switch"
+	PlatformOculus      Platform = "oculus"
+	PlatformSource      Platform = "source"
+)
+
+// Portal is the store or service a game was launched from
+type Portal string
+
+// Portal values
+const (
+	PortalNone     Portal = "none"
+	PortalApple    Portal = "apple"
+	PortalDiscord  Portal = "discord"
+	PortalEpic     Portal = "egs"
+	PortalFacebook Portal = "facebook"
+	PortalGOG      Portal = "gog"
+	PortalGoogle   Portal = "google"
+	PortalItchio   Portal = "itchio"
+	PortalNintendo Portal = "nintendo"
+	PortalOpenID   Portal = "openid"
+	PortalPSN      Portal = "psn"
+	PortalSteam    Portal = "steam"
+	PortalXboxLive Portal = "xboxlive"
+)
+
+// FilePlatform is the status of a modfile on a single platform
+type FilePlatform struct {
+	Platform Platform           `json:"platform"`
+	Status   FilePlatformStatus `json:"status"`
+}
+
+// FilePlatformStatus is whether a modfile has been approved for a platform
+type FilePlatformStatus int
+
+// FilePlatformStatus values
+const (
+	FilePlatformPending  FilePlatformStatus = 0
+	FilePlatformApproved FilePlatformStatus = 1
+	FilePlatformDenied   FilePlatformStatus = 2
+)
+
+func (s FilePlatformStatus) String() string {
+	switch s {
+	case FilePlatformPending:
+		return "pending"
+	case FilePlatformApproved:
+		return "approved"
+	case FilePlatformDenied:
+		return "denied"
+	}
+	return "platform status(" + strconv.Itoa(int(s)) + ")"
+}
+
+// PlatformStatus returns the file's status on platform and whether the file targets it
+func (f *File) PlatformStatus(platform Platform) (FilePlatformStatus, bool) {
+	for _, p := range f.Platforms {
+		if p.Platform == platform {
+			return p.Status, true
+		}
+	}
+	return FilePlatformPending, false
+}
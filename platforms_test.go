@@ -0,0 +1,98 @@
+package gomodio
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestPlatformPortalHeaders(t *testing.T) {
+	tests := []struct {
+		name             string
+		platform         Platform
+		portal           Portal
+		wantPlatform     string
+		wantPortal       string
+		wantPlatformSent bool
+		wantPortalSent   bool
+	}{
+		{"unset", "", "", "", "", false, false},
+		{"platform", PlatformPS5, "", "ps5", "", true, false},
+		{"portal", "", PortalEpic, "", "egs", false, true},
+		{"both", PlatformSwitch, PortalNintendo, "switch", "nintendo", true, true},
+	}
+	for _, tt := range tests {
+		user := NewUser("key", "")
+		user.SetPlatform(tt.platform)
+		user.SetPortal(tt.portal)
+		if user.Platform() != tt.platform || user.Portal() != tt.portal {
+			t.Errorf("%s: getters = %q, %q", tt.name, user.Platform(), user.Portal())
+		}
+		req, err := user.newRequest("GET", "https://api.mod.io/v1/games", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, platformSent := req.Header["X-Modio-Platform"]
+		_, portalSent := req.Header["X-Modio-Portal"]
+		if platformSent != tt.wantPlatformSent || req.Header.Get("X-Modio-Platform") != tt.wantPlatform {
+			t.Errorf("%s: X-Modio-Platform = %q", tt.name, req.Header.Get("X-Modio-Platform"))
+		}
+		if portalSent != tt.wantPortalSent || req.Header.Get("X-Modio-Portal") != tt.wantPortal {
+			t.Errorf("%s: X-Modio-Portal = %q", tt.name, req.Header.Get("X-Modio-Portal"))
+		}
+	}
+}
+
+func TestFilePlatformStatus(t *testing.T) {
+	var f File
+	err := json.Unmarshal([]byte(`{"platforms":[{"platform":"windows","status":1},{"platform":"ps5","status":2}]}`), &f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		platform Platform
+		want     FilePlatformStatus
+		targeted bool
+	}{
+		{PlatformWindows, FilePlatformApproved, true},
+		{PlatformPS5, FilePlatformDenied, true},
+		{PlatformSwitch, FilePlatformPending, false},
+	}
+	for _, tt := range tests {
+		status, ok := f.PlatformStatus(tt.platform)
+		if status != tt.want || ok != tt.targeted {
+			t.Errorf("PlatformStatus(%s) = %v, %v, want %v, %v", tt.platform, status, ok, tt.want, tt.targeted)
+		}
+	}
+	if s := FilePlatformStatus(7).String(); s != "platform status(7)" {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestModfilePlatformFields(t *testing.T) {
+	v := (&AddModfileOptions{Filedata: "mod.zip", Platforms: []Platform{PlatformWindows, PlatformLinux}}).values()
+	if got := v["platforms[]"]; !reflect.DeepEqual(got, []string{"windows", "linux"}) {
+		t.Errorf("platforms[] = %q", got)
+	}
+
+	var form url.Values
+	user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		w.Write([]byte(`{"id":3}`))
+	})
+	user.SetOAuth2Token("token")
+	if _, err := user.ManageModfilePlatforms(3, 2, 1, nil, nil); err == nil {
+		t.Error("no platforms accepted")
+	}
+	_, err := user.ManageModfilePlatforms(3, 2, 1, []Platform{PlatformPS5}, []Platform{PlatformXboxOne, PlatformSwitch})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := url.Values{"approved[]": {"ps5"}, "denied[]": {"xboxone", "switch"}}
+	if !reflect.DeepEqual(form, want) {
+		t.Errorf("form = %v, want %v", form, want)
+	}
+}
@@ -0,0 +1,422 @@
+package gomodio
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Manifest describes the desired state of a mod. It is usually kept in the
+// mod's repository as modio.yaml or modio.json and loaded with LoadManifest
+type Manifest struct {
+	GameID      int    `json:"game_id" yaml:"game_id"`
+	ModID       int    `json:"mod_id" yaml:"mod_id"`
+	Name        string `json:"name" yaml:"name"`
+	Summary     string `json:"summary" yaml:"summary"`
+	Description string `json:"description" yaml:"description"`
+	Homepage    string `json:"homepage" yaml:"homepage"`
+	Logo        string `json:"logo" yaml:"logo"`
+	Visible     *bool  `json:"visible" yaml:"visible"`
+	// Tags and Metadata are left alone when absent from the manifest. An
+	// empty list or map removes every tag or metadata KVP from the mod
+	Tags     []string          `json:"tags" yaml:"tags"`
+	Metadata map[string]string `json:"metadata" yaml:"metadata"`
+	Images   []string          `json:"images" yaml:"images"`
+	Modfile  *ManifestModfile  `json:"modfile" yaml:"modfile"`
+
+	dir string
+}
+
+// ManifestModfile describes the modfile artifact of a Manifest
+type ManifestModfile struct {
+	Path         string `json:"path" yaml:"path"`
+	Version      string `json:"version" yaml:"version"`
+	Changelog    string `json:"changelog" yaml:"changelog"`
+	Active       *bool  `json:"active" yaml:"active"`
+	MetadataBlob string `json:"metadata_blob" yaml:"metadata_blob"`
+}
+
+// PublishStep is a single change Publish makes to bring a mod in line with its Manifest
+type PublishStep struct {
+	Op    string
+	Field string
+	Old   string
+	New   string
+
+	run func() error
+}
+
+// PublishPlan is the ordered list of changes Publish applies to a mod
+type PublishPlan struct {
+	GameID int
+	ModID  int
+	Steps  []PublishStep
+}
+
+// LoadManifest reads a manifest from a .yaml, .yml or .json file.
+// Relative file paths in the manifest are resolved against the manifest's directory
+func LoadManifest(path string) (m *Manifest, err error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m = &Manifest{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, m)
+	case ".json":
+		err = json.Unmarshal(b, m)
+	default:
+		return nil, errors.New("manifest must be a .yaml, .yml or .json file")
+	}
+	if err != nil {
+		return nil, err
+	}
+	m.dir = filepath.Dir(path)
+	return m, nil
+}
+
+func (m *Manifest) path(p string) string {
+	if p == "" || filepath.IsAbs(p) || m.dir == "" {
+		return p
+	}
+	return filepath.Join(m.dir, p)
+}
+
+// String returns the step as a single diff line
+func (s PublishStep) String() string {
+	switch s.Op {
+	case "~":
+		return fmt.Sprintf("~ %s: %q -> %q", s.Field, s.Old, s.New)
+	case "-":
+		return fmt.Sprintf("- %s: %q", s.Field, s.Old)
+	default:
+		return fmt.Sprintf("+ %s: %q", s.Field, s.New)
+	}
+}
+
+// String returns the plan as a diff, one step per line
+func (p *PublishPlan) String() string {
+	var sb strings.Builder
+	if p.ModID == 0 {
+		sb.WriteString("mod (new) in game " + strconv.Itoa(p.GameID) + "\n")
+	} else {
+		sb.WriteString("mod " + strconv.Itoa(p.ModID) + " in game " + strconv.Itoa(p.GameID) + "\n")
+	}
+	if len(p.Steps) == 0 {
+		sb.WriteString("  no changes\n")
+	}
+	for _, s := range p.Steps {
+		sb.WriteString("  " + s.String() + "\n")
+	}
+	return sb.String()
+}
+
+// Publish reconciles the remote mod with the manifest, creating the mod when
+// the manifest has no mod_id. The planned diff is written to out when out is
+// not nil. With dryRun set nothing is changed on mod.io
+func (user *User) Publish(m *Manifest, dryRun bool, out io.Writer) (plan *PublishPlan, err error) {
+	if m.GameID == 0 {
+		return nil, errors.New("manifest requires game_id")
+	}
+	if m.Name == "" || m.Summary == "" {
+		return nil, errors.New("manifest requires name and summary")
+	}
+	plan = &PublishPlan{GameID: m.GameID, ModID: m.ModID}
+	var current *Mod
+	if m.ModID != 0 {
+		current, err = user.GetMod(m.ModID, m.GameID, nil)
+		if err != nil {
+			return nil, err
+		}
+	} else if m.Logo == "" {
+		return nil, errors.New("manifest requires a logo to add a new mod")
+	}
+	plan.planMod(user, m, current)
+	plan.planTags(user, m, current)
+	plan.planMetadata(user, m, current)
+	plan.planMedia(user, m, current)
+	plan.planModfile(user, m, current)
+	if out != nil {
+		_, err = io.WriteString(out, plan.String())
+		if err != nil {
+			return plan, err
+		}
+	}
+	if dryRun {
+		return plan, nil
+	}
+	for _, s := range plan.Steps {
+		err = s.run()
+		if err != nil {
+			return plan, fmt.Errorf("publish %s: %v", s.String(), err)
+		}
+	}
+	m.ModID = plan.ModID
+	return plan, nil
+}
+
+func (p *PublishPlan) add(op, field, old, new string, run func() error) {
+	p.Steps = append(p.Steps, PublishStep{Op: op, Field: field, Old: old, New: new, run: run})
+}
+
+func (p *PublishPlan) planMod(user *User, m *Manifest, current *Mod) {
+	var visible *Visibility
+	if m.Visible != nil {
+		v := VisibilityHidden
+		if *m.Visible {
+			v = VisibilityPublic
+		}
+		visible = &v
+	}
+	if current == nil {
+		options := &AddModOptions{
+			Logo:        m.path(m.Logo),
+			Name:        m.Name,
+			Summary:     m.Summary,
+			Description: optionalString(m.Description),
+			HomepageURL: optionalString(m.Homepage),
+			Visible:     visible,
+		}
+		p.add("+", "mod", "", m.Name, func() error {
+			mod, err := user.AddMod(options, m.GameID)
+			if err != nil {
+				return err
+			}
+			p.ModID = mod.ID
+			return nil
+		})
+		return
+	}
+	changes := &EditModOptions{}
+	first := len(p.Steps)
+	diff := func(field, old, new string, set func()) {
+		if new != "" && new != old {
+			set()
+			p.add("~", field, old, new, noop)
+		}
+	}
+	diff("name", current.Name, m.Name, func() { changes.Name = String(m.Name) })
+	diff("summary", current.Summary, m.Summary, func() { changes.Summary = String(m.Summary) })
+	diff("description", current.Description, m.Description, func() { changes.Description = String(m.Description) })
+	diff("homepage_url", current.HomepageURL, m.Homepage, func() { changes.HomepageURL = String(m.Homepage) })
+	if visible != nil {
+		diff("visible", current.Visible.String(), visible.String(), func() { changes.Visible = visible })
+	}
+	if len(p.Steps) > first {
+		p.Steps[first].run = func() error {
+			_, err := user.EditMod(p.ModID, p.GameID, changes)
+			return err
+		}
+	}
+	if m.Logo != "" && filepath.Base(m.Logo) != current.Logo.Filename {
+		logo := m.path(m.Logo)
+		p.add("~", "logo", current.Logo.Filename, filepath.Base(m.Logo), func() error {
+			_, err := user.AddModMedia(p.ModID, p.GameID, &AddModMediaOptions{Logo: logo})
+			return err
+		})
+	}
+}
+
+func (p *PublishPlan) planTags(user *User, m *Manifest, current *Mod) {
+	if m.Tags == nil {
+		return
+	}
+	existing := map[string]bool{}
+	if current != nil {
+		for _, t := range current.Tags {
+			existing[t.Name] = true
+		}
+	}
+	wanted := map[string]bool{}
+	var added, removed []string
+	firstAdded := len(p.Steps)
+	for _, t := range m.Tags {
+		if wanted[t] {
+			continue
+		}
+		wanted[t] = true
+		if !existing[t] {
+			added = append(added, t)
+			p.add("+", "tag", "", t, noop)
+		}
+	}
+	firstRemoved := len(p.Steps)
+	for _, t := range sortedKeys(existing) {
+		if !wanted[t] {
+			removed = append(removed, t)
+			p.add("-", "tag", t, "", noop)
+		}
+	}
+	if len(added) > 0 {
+		p.Steps[firstAdded].run = func() error {
+			_, err := user.AddModTags(added, p.ModID, p.GameID)
+			return err
+		}
+	}
+	if len(removed) > 0 {
+		p.Steps[firstRemoved].run = func() error {
+			return user.DeleteModTags(removed, p.ModID, p.GameID)
+		}
+	}
+}
+
+func (p *PublishPlan) planMetadata(user *User, m *Manifest, current *Mod) {
+	if m.Metadata == nil {
+		return
+	}
+	existing := map[string]bool{}
+	if current != nil {
+		for _, kvp := range current.MetadataKvp {
+			existing[kvp.Metakey+":"+kvp.Metavalue] = true
+		}
+	}
+	wanted := map[string]bool{}
+	var added, removed []string
+	firstAdded := len(p.Steps)
+	for _, k := range sortedKeys(m.Metadata) {
+		kvp := k + ":" + m.Metadata[k]
+		wanted[kvp] = true
+		if !existing[kvp] {
+			added = append(added, kvp)
+			p.add("+", "metadata", "", kvp, noop)
+		}
+	}
+	firstRemoved := len(p.Steps)
+	for _, kvp := range sortedKeys(existing) {
+		if !wanted[kvp] {
+			removed = append(removed, kvp)
+			p.add("-", "metadata", kvp, "", noop)
+		}
+	}
+	if len(added) > 0 {
+		p.Steps[firstAdded].run = func() error {
+			_, err := user.AddModMetadata(added, p.ModID, p.GameID)
+			return err
+		}
+	}
+	if len(removed) > 0 {
+		p.Steps[firstRemoved].run = func() error {
+			return user.DeleteModMetadata(removed, p.ModID, p.GameID)
+		}
+	}
+}
+
+func (p *PublishPlan) planMedia(user *User, m *Manifest, current *Mod) {
+	existing := map[string]bool{}
+	if current != nil {
+		for _, img := range current.Media.Images {
+			existing[img.Filename] = true
+		}
+	}
+	for _, img := range m.Images {
+		if existing[filepath.Base(img)] {
+			continue
+		}
+		image := m.path(img)
+		p.add("+", "image", "", filepath.Base(img), func() error {
+			_, err := user.AddModMedia(p.ModID, p.GameID, &AddModMediaOptions{Images: []string{image}})
+			return err
+		})
+	}
+}
+
+func (p *PublishPlan) planModfile(user *User, m *Manifest, current *Mod) {
+	mf := m.Modfile
+	if mf == nil || mf.Path == "" {
+		return
+	}
+	path := m.path(mf.Path)
+	label := mf.Version
+	if label == "" {
+		label = filepath.Base(mf.Path)
+	}
+	if current != nil && current.Modfile.ID != 0 {
+		if mf.Version != "" && mf.Version == current.Modfile.Version {
+			return
+		}
+		// Without a version the live modfile is kept when its hash matches
+		if mf.Version == "" {
+			sum, err := fileMD5(path)
+			if err == nil && sum == current.Modfile.Filehash.Md5 {
+				return
+			}
+		}
+	}
+	options := &AddModfileOptions{
+		Filedata:  path,
+		Version:   optionalString(mf.Version),
+		Changelog: optionalString(mf.Changelog),
+		Active:    mf.Active,
+	}
+	if mf.MetadataBlob != "" {
+		options.MetadataBlob = String(mf.MetadataBlob)
+	}
+	run := func() error {
+		_, err := user.AddModfile(p.ModID, p.GameID, options)
+		return err
+	}
+	if current == nil || current.Modfile.ID == 0 {
+		p.add("+", "modfile", "", label, run)
+		return
+	}
+	old := current.Modfile.Version
+	if old == "" {
+		old = current.Modfile.Filename
+	}
+	p.add("~", "modfile", old, label, run)
+}
+
+// fileMD5 returns the hex md5 of a file, as mod.io reports in Filehash
+func fileMD5(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := md5.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func noop() error {
+	return nil
+}
+
+// optionalString returns nil for an empty manifest field so it is left unset
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch v := m.(type) {
+	case map[string]string:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]bool:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
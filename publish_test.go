@@ -0,0 +1,152 @@
+package gomodio
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func planSteps(p *PublishPlan) []string {
+	var steps []string
+	for _, s := range p.Steps {
+		steps = append(steps, s.String())
+	}
+	return steps
+}
+
+func existingMod() *Mod {
+	m := &Mod{
+		ID:          7,
+		GameID:      1,
+		Name:        "Old Name",
+		Summary:     "Same summary",
+		Visible:     VisibilityPublic,
+		Tags:        []ModTag{{Name: "Maps"}, {Name: "Legacy"}},
+		MetadataKvp: []MetadataKVP{{Metakey: "engine", Metavalue: "4.1"}},
+	}
+	m.Logo.Filename = "logo.png"
+	m.Modfile.ID = 3
+	m.Modfile.Version = "1.0.0"
+	return m
+}
+
+func TestPlanModNew(t *testing.T) {
+	p := &PublishPlan{GameID: 1}
+	p.planMod(NewUser("key", ""), &Manifest{GameID: 1, Name: "My Mod", Summary: "s", Logo: "logo.png"}, nil)
+	want := []string{`+ mod: "My Mod"`}
+	if got := planSteps(p); !reflect.DeepEqual(got, want) {
+		t.Errorf("steps = %q, want %q", got, want)
+	}
+}
+
+func TestPlanModExisting(t *testing.T) {
+	p := &PublishPlan{GameID: 1, ModID: 7}
+	m := &Manifest{GameID: 1, ModID: 7, Name: "New Name", Summary: "Same summary", Logo: "art/logo2.png", Visible: Bool(false)}
+	p.planMod(NewUser("key", ""), m, existingMod())
+	want := []string{
+		`~ name: "Old Name" -> "New Name"`,
+		`~ visible: "public" -> "hidden"`,
+		`~ logo: "logo.png" -> "logo2.png"`,
+	}
+	if got := planSteps(p); !reflect.DeepEqual(got, want) {
+		t.Errorf("steps = %q, want %q", got, want)
+	}
+}
+
+func TestPlanTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    []string
+		current *Mod
+		want    []string
+	}{
+		{"new mod", []string{"Maps"}, nil, []string{`+ tag: "Maps"`}},
+		{"unmanaged", nil, existingMod(), nil},
+		{"clear", []string{}, existingMod(), []string{`- tag: "Legacy"`, `- tag: "Maps"`}},
+		{"reconcile", []string{"Maps", "Multiplayer"}, existingMod(), []string{`+ tag: "Multiplayer"`, `- tag: "Legacy"`}},
+		{"duplicates", []string{"Maps", "Multiplayer", "Multiplayer", "Maps"}, existingMod(), []string{`+ tag: "Multiplayer"`, `- tag: "Legacy"`}},
+	}
+	for _, tt := range tests {
+		p := &PublishPlan{GameID: 1, ModID: 7}
+		p.planTags(NewUser("key", ""), &Manifest{Tags: tt.tags}, tt.current)
+		if got := planSteps(p); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: steps = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPlanMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		current  *Mod
+		want     []string
+	}{
+		{"new mod", map[string]string{"engine": "4.2"}, nil, []string{`+ metadata: "engine:4.2"`}},
+		{"unmanaged", nil, existingMod(), nil},
+		{"clear", map[string]string{}, existingMod(), []string{`- metadata: "engine:4.1"`}},
+		{"reconcile", map[string]string{"engine": "4.2"}, existingMod(), []string{`+ metadata: "engine:4.2"`, `- metadata: "engine:4.1"`}},
+	}
+	for _, tt := range tests {
+		p := &PublishPlan{GameID: 1, ModID: 7}
+		p.planMetadata(NewUser("key", ""), &Manifest{Metadata: tt.metadata}, tt.current)
+		if got := planSteps(p); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: steps = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPlanModfile(t *testing.T) {
+	dir := t.TempDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "mod.zip"), []byte("mod contents"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := fileMD5(filepath.Join(dir, "mod.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameHash := existingMod()
+	sameHash.Modfile.Filehash.Md5 = sum
+	tests := []struct {
+		name    string
+		modfile *ManifestModfile
+		current *Mod
+		want    []string
+	}{
+		{"new mod", &ManifestModfile{Path: "mod.zip", Version: "1.0.0"}, nil, []string{`+ modfile: "1.0.0"`}},
+		{"new mod without version", &ManifestModfile{Path: "mod.zip"}, nil, []string{`+ modfile: "mod.zip"`}},
+		{"no modfile", nil, existingMod(), nil},
+		{"same version", &ManifestModfile{Path: "mod.zip", Version: "1.0.0"}, existingMod(), nil},
+		{"new version", &ManifestModfile{Path: "mod.zip", Version: "1.1.0"}, existingMod(), []string{`~ modfile: "1.0.0" -> "1.1.0"`}},
+		{"no version, same hash", &ManifestModfile{Path: "mod.zip"}, sameHash, nil},
+		{"no version, new hash", &ManifestModfile{Path: "mod.zip"}, existingMod(), []string{`~ modfile: "1.0.0" -> "mod.zip"`}},
+	}
+	for _, tt := range tests {
+		p := &PublishPlan{GameID: 1, ModID: 7}
+		p.planModfile(NewUser("key", ""), &Manifest{Modfile: tt.modfile, dir: dir}, tt.current)
+		if got := planSteps(p); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: steps = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadManifestEmptyVersusMissing(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "modio.yaml")
+	err := ioutil.WriteFile(path, []byte("game_id: 1\nname: a\nsummary: b\ntags: []\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := LoadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Tags == nil || len(m.Tags) != 0 {
+		t.Errorf("tags = %#v, want empty non-nil slice", m.Tags)
+	}
+	if m.Metadata != nil {
+		t.Errorf("metadata = %#v, want nil", m.Metadata)
+	}
+}
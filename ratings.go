@@ -2,9 +2,7 @@ package gomodio
 
 import (
 	"encoding/json"
-	"errors"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -12,49 +10,153 @@ import (
 	"time"
 )
 
+// Rating is a user's rating of a mod
+type Rating int
+
+// Ratings a user can give a mod. RatingNone clears a previous rating
+const (
+	RatingNegative Rating = -1
+	RatingNone     Rating = 0
+	RatingPositive Rating = 1
+)
+
+func (r Rating) String() string {
+	switch r {
+	case RatingPositive:
+		return "positive"
+	case RatingNegative:
+		return "negative"
+	case RatingNone:
+		return "none"
+	}
+	return "Rating(" + strconv.Itoa(int(r)) + ")"
+}
+
+// errorRefAlreadyRated is the error_ref mod.io sends when the user already gave the same rating
+const errorRefAlreadyRated = 15028
+
+// UserRatings struct maps to the JSON response of Get User Ratings
+type UserRatings struct {
+	Data         []UserRating `json:"data"`
+	ResultCount  int          `json:"result_count"`
+	ResultOffset int          `json:"result_offset"`
+	ResultLimit  int          `json:"result_limit"`
+	ResultTotal  int          `json:"result_total"`
+}
+
+// UserRating is a rating the authenticated user gave a mod
+type UserRating struct {
+	GameID    int       `json:"game_id"`
+	ModID     int       `json:"mod_id"`
+	Rating    Rating    `json:"rating"`
+	DateAdded Timestamp `json:"date_added"`
+}
+
 // AddModRating adds a rating to a mod. Requires OAuth2
 func (user *User) AddModRating(isPositive bool, modID, gameID int) (m *Message, err error) {
-	if user.OAuth2Token() == "" {
-		return nil, errors.New("requires oauth2 authentication")
-	}
-	var rating string
 	if isPositive {
-		rating = "1"
-	} else {
-		rating = "-1"
+		return user.SetModRating(RatingPositive, modID, gameID)
+	}
+	return user.SetModRating(RatingNegative, modID, gameID)
+}
+
+// SetModRating sets, changes or clears the user's rating of a mod. Rating a mod with
+// the value it already has is not an error. Requires OAuth2
+func (user *User) SetModRating(rating Rating, modID, gameID int) (m *Message, err error) {
+	err = user.requireToken()
+	if err != nil {
+		return nil, err
 	}
 	reqBody := url.Values{
-		"rating": {rating},
+		"rating": {strconv.Itoa(int(rating))},
 	}
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/ratings", strings.NewReader(reqBody.Encode()))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/ratings", strings.NewReader(reqBody.Encode()))
 	if err != nil {
 		return nil, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
 	if resp.StatusCode != 201 {
 		var errObj ErrorCase
 		e := json.Unmarshal(body, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return nil, e
+		}
+		if errObj.Error.Code == errorRefAlreadyRated {
+			return &Message{Code: resp.StatusCode, Message: errObj.Error.Message}, nil
 		}
 		return nil, HandleResponseError(errObj)
 	}
+	err = json.Unmarshal(body, &m)
 	if err != nil {
 		return nil, err
 	}
-	err = json.Unmarshal(body, &m)
+	return m, nil
+}
+
+// GetUserRatings gets the ratings the authenticated user has given. Requires OAuth2
+func (user *User) GetUserRatings(options map[string]string) (res *UserRatings, err error) {
+	err = user.requireToken()
 	if err != nil {
 		return nil, err
 	}
-	return m, nil
+	var queryStr string
+	if options == nil {
+		queryStr = "api_key=" + user.APIKey()
+	} else {
+		options["api_key"] = user.APIKey()
+		queryStr = ParseArgsGet(options)
+	}
+	client := http.Client{Timeout: time.Duration(5 * time.Second)}
+	req, err := user.newRequest("GET", "https://api.mod.io/v1/me/ratings?"+queryStr, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := user.do(&client, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		var errObj ErrorCase
+		e := json.Unmarshal(body, &errObj)
+		if e != nil {
+			return nil, e
+		}
+		return nil, HandleResponseError(errObj)
+	}
+	err = json.Unmarshal(body, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetModRating gets the authenticated user's rating of a mod, RatingNone if they have not rated it
+func (user *User) GetModRating(modID, gameID int) (Rating, error) {
+	ratings, err := user.GetUserRatings(map[string]string{
+		"game_id": strconv.Itoa(gameID),
+		"mod_id":  strconv.Itoa(modID),
+	})
+	if err != nil {
+		return RatingNone, err
+	}
+	for _, r := range ratings.Data {
+		if r.ModID == modID && r.GameID == gameID {
+			return r.Rating, nil
+		}
+	}
+	return RatingNone, nil
 }
@@ -0,0 +1,65 @@
+package gomodio
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSetModRating(t *testing.T) {
+	tests := []struct {
+		name     string
+		rating   Rating
+		status   int
+		body     string
+		wantSent string
+		wantCode int
+		wantErr  bool
+	}{
+		{"positive", RatingPositive, 201, `{"code":201,"message":"rated"}`, "1", 201, false},
+		{"clear", RatingNone, 201, `{"code":201,"message":"cleared"}`, "0", 201, false},
+		{"already rated", RatingNegative, 400, `{"error":{"code":400,"error_ref":15028,"message":"already submitted this rating"}}`, "-1", 400, false},
+		{"other error", RatingPositive, 400, `{"error":{"code":400,"error_ref":15043,"message":"rejected"}}`, "1", 0, true},
+		{"undecodable", RatingPositive, 502, `<html>Bad Gateway</html>`, "1", 0, true},
+	}
+	for _, tt := range tests {
+		var sent string
+		user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v1/games/1/mods/2/ratings" {
+				t.Errorf("%s: path = %s", tt.name, r.URL.Path)
+			}
+			r.ParseForm()
+			sent = r.PostForm.Get("rating")
+			w.WriteHeader(tt.status)
+			w.Write([]byte(tt.body))
+		})
+		user.SetOAuth2Token("token")
+		m, err := user.SetModRating(tt.rating, 2, 1)
+		if sent != tt.wantSent {
+			t.Errorf("%s: rating = %q, want %q", tt.name, sent, tt.wantSent)
+		}
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (m == nil || m.Code != tt.wantCode) {
+			t.Errorf("%s: message = %+v, want code %d", tt.name, m, tt.wantCode)
+		}
+	}
+}
+
+func TestGetUserRatings(t *testing.T) {
+	user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/me/ratings" || r.URL.Query().Get("game_id") != "1" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"data":[{"game_id":1,"mod_id":2,"rating":-1,"date_added":1700000000}],"result_total":1}`))
+	})
+	user.SetOAuth2Token("token")
+	res, err := user.GetUserRatings(map[string]string{"game_id": "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Data) != 1 || res.Data[0].Rating != RatingNegative || res.Data[0].ModID != 2 {
+		t.Errorf("ratings = %+v", res.Data)
+	}
+}
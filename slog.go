@@ -0,0 +1,77 @@
+//go:build go1.21
+// +build go1.21
+
+package gomodio
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHook is a Hook logging requests to a slog.Logger. URLs and errors are
+// logged with api keys and bearer tokens redacted
+type SlogHook struct {
+	logger *slog.Logger
+}
+
+// NewSlogHook returns a hook logging to logger, or slog.Default when logger is nil
+func NewSlogHook(logger *slog.Logger) *SlogHook {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SlogHook{logger: logger}
+}
+
+func (h *SlogHook) attrs(e *RequestEvent) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("method", e.Method),
+		slog.String("path", e.Path),
+		slog.String("url", RedactURL(e.URL)),
+		slog.Int("attempt", e.Attempt),
+	}
+	if e.Status != 0 {
+		attrs = append(attrs,
+			slog.Int("status", e.Status),
+			slog.Duration("duration", e.Duration),
+			slog.Int64("request_bytes", e.RequestBytes),
+			slog.Int64("response_bytes", e.ResponseBytes),
+		)
+	}
+	if e.RateLimit.Limit != 0 {
+		attrs = append(attrs,
+			slog.Int("ratelimit_limit", e.RateLimit.Limit),
+			slog.Int("ratelimit_remaining", e.RateLimit.Remaining),
+		)
+	}
+	if e.RateLimit.RetryAfter != 0 {
+		attrs = append(attrs, slog.Duration("retry_after", e.RateLimit.RetryAfter))
+	}
+	if e.Err != nil {
+		attrs = append(attrs, slog.String("error", RedactURL(e.Err.Error())))
+	}
+	return attrs
+}
+
+// BeforeRequest logs the request at debug level
+func (h *SlogHook) BeforeRequest(e *RequestEvent) {
+	h.logger.LogAttrs(context.Background(), slog.LevelDebug, "modio request", h.attrs(e)...)
+}
+
+// AfterResponse logs the response at info level, or warn level for error statuses
+func (h *SlogHook) AfterResponse(e *RequestEvent) {
+	level := slog.LevelInfo
+	if e.Status >= 400 {
+		level = slog.LevelWarn
+	}
+	h.logger.LogAttrs(context.Background(), level, "modio response", h.attrs(e)...)
+}
+
+// OnRetry logs the retry at warn level
+func (h *SlogHook) OnRetry(e *RequestEvent) {
+	h.logger.LogAttrs(context.Background(), slog.LevelWarn, "modio retry", h.attrs(e)...)
+}
+
+// OnError logs the failure at error level
+func (h *SlogHook) OnError(e *RequestEvent) {
+	h.logger.LogAttrs(context.Background(), slog.LevelError, "modio request failed", h.attrs(e)...)
+}
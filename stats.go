@@ -3,23 +3,21 @@ package gomodio
 import (
 	"encoding/json"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"net/url"
 	"strconv"
-	"strings"
 	"time"
 )
 
 // GameStats struct represents a game's stats
 type GameStats struct {
-	GameID                    int `json:"game_id"`
-	ModsCountTotal            int `json:"mods_count_total"`
-	ModsDownloadsToday        int `json:"mods_downloads_today"`
-	ModsDownloadsTotal        int `json:"mods_downloads_total"`
-	ModsDownloadsDailyAverage int `json:"mods_downloads_daily_average"`
-	ModsSubscribersTotal      int `json:"mods_subscribers_total"`
-	DateExpires               int `json:"date_expires"`
+	GameID                    int       `json:"game_id"`
+	ModsCountTotal            int       `json:"mods_count_total"`
+	ModsDownloadsToday        int       `json:"mods_downloads_today"`
+	ModsDownloadsTotal        int       `json:"mods_downloads_total"`
+	ModsDownloadsDailyAverage int       `json:"mods_downloads_daily_average"`
+	ModsSubscribersTotal      int       `json:"mods_subscribers_total"`
+	DateExpires               Timestamp `json:"date_expires"`
 }
 
 // ModStats struct represents a group of stats of a mod
@@ -33,32 +31,28 @@ type ModStats struct {
 
 // Stats struct represents a stats object
 type Stats struct {
-	ModID                     int     `json:"mod_id"`
-	PopularityRankPosition    int     `json:"popularity_rank_position"`
-	PopularityRankTotalMods   int     `json:"popularity_rank_total_mods"`
-	DownloadsTotal            int     `json:"downloads_total"`
-	SubscribersTotal          int     `json:"subscribers_total"`
-	RatingsTotal              int     `json:"ratings_total"`
-	RatingsPositive           int     `json:"ratings_positive"`
-	RatingsNegative           int     `json:"ratings_negative"`
-	RatingsPercentagePositive int     `json:"ratings_percentage_positive"`
-	RatingsWeightedAggregate  float64 `json:"ratings_weighted_aggregate"`
-	RatingsDisplayText        string  `json:"ratings_display_text"`
-	DateExpires               int     `json:"date_expires"`
+	ModID                     int       `json:"mod_id"`
+	PopularityRankPosition    int       `json:"popularity_rank_position"`
+	PopularityRankTotalMods   int       `json:"popularity_rank_total_mods"`
+	DownloadsTotal            int       `json:"downloads_total"`
+	SubscribersTotal          int       `json:"subscribers_total"`
+	RatingsTotal              int       `json:"ratings_total"`
+	RatingsPositive           int       `json:"ratings_positive"`
+	RatingsNegative           int       `json:"ratings_negative"`
+	RatingsPercentagePositive int       `json:"ratings_percentage_positive"`
+	RatingsWeightedAggregate  float64   `json:"ratings_weighted_aggregate"`
+	RatingsDisplayText        string    `json:"ratings_display_text"`
+	DateExpires               Timestamp `json:"date_expires"`
 }
 
 // GetGameStats gets a game's stats
 func (u *User) GetGameStats(gameID int) (gs *GameStats, err error) {
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"mods/stats?api_key="+u.APIKey(), nil)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := u.newRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/stats?api_key="+u.APIKey(), nil)
 	if err != nil {
 		return nil, err
 	}
-	if u.OAuth2Token() != "" || len(u.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+u.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := u.do(&client, req)
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +62,7 @@ func (u *User) GetGameStats(gameID int) (gs *GameStats, err error) {
 		var errObj ErrorCase
 		e := json.Unmarshal(body, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return nil, e
 		}
 		return nil, HandleResponseError(errObj)
 	}
@@ -85,15 +79,11 @@ func (u *User) GetGameStats(gameID int) (gs *GameStats, err error) {
 // GetModStats gets a mod's stats
 func (u *User) GetModStats(modID, gameID int) (s *Stats, err error) {
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"mods/"+strconv.Itoa(modID)+"/stats?api_key="+u.APIKey(), nil)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := u.newRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/stats?api_key="+u.APIKey(), nil)
 	if err != nil {
 		return nil, err
 	}
-	if u.OAuth2Token() != "" || len(u.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+u.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := u.do(&client, req)
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +93,7 @@ func (u *User) GetModStats(modID, gameID int) (s *Stats, err error) {
 		var errObj ErrorCase
 		e := json.Unmarshal(body, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return nil, e
 		}
 		return nil, HandleResponseError(errObj)
 	}
@@ -117,44 +107,62 @@ func (u *User) GetModStats(modID, gameID int) (s *Stats, err error) {
 	return s, nil
 }
 
-// GetModsStats gets a game's mod's stats
-func (u *User) GetModsStats(gameID int, options map[string]int) (ms *ModStats, err error) {
-	var queryBody url.Values
-	if options != nil {
-		for k, v := range options {
-			queryBody.Add(k, strconv.Itoa(v))
-		}
+// GetModsStats gets the stats of a game's mods matching filter. A nil filter returns the first page of every mod's stats
+func (u *User) GetModsStats(gameID int, filter *ModStatsFilter) (ms *ModStats, err error) {
+	query := url.Values{}
+	if filter != nil {
+		query = filter.values()
 	}
-	queryBody.Add("api_key", u.APIKey())
+	query.Set("api_key", u.APIKey())
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"mods/stats", strings.NewReader(queryBody.Encode()))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := u.newRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/stats?"+query.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
-	if u.OAuth2Token() != "" || len(u.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+u.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := u.do(&client, req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
 	if resp.StatusCode != 200 {
 		var errObj ErrorCase
 		e := json.Unmarshal(body, &errObj)
 		if e != nil {
-			log.Fatalln(e)
+			return nil, e
 		}
 		return nil, HandleResponseError(errObj)
 	}
-	if err != nil {
-		return nil, err
-	}
 	err = json.Unmarshal(body, &ms)
 	if err != nil {
 		return nil, err
 	}
 	return ms, nil
 }
+
+// GetAllModsStats pages through GetModsStats, returning the stats of every mod matching filter.
+// The filter's Limit sets the page size and its Offset where to start
+func (u *User) GetAllModsStats(gameID int, filter *ModStatsFilter) ([]Stats, error) {
+	page := ModStatsFilter{}
+	if filter != nil {
+		page = *filter
+	}
+	if page.Limit == 0 {
+		page.Limit = modPageSize
+	}
+	var all []Stats
+	for {
+		ms, err := u.GetModsStats(gameID, &page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, ms.Data...)
+		page.Offset += len(ms.Data)
+		if len(ms.Data) == 0 || page.Offset >= ms.ResultTotal {
+			return all, nil
+		}
+	}
+}
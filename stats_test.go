@@ -0,0 +1,81 @@
+package gomodio
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestModStatsFilterValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter ModStatsFilter
+		want   url.Values
+	}{
+		{"empty", ModStatsFilter{}, url.Values{}},
+		{"mods", ModStatsFilter{ModIDs: []int{1, 2, 3}}, url.Values{"mod_id-in": {"1,2,3"}}},
+		{"bounds", ModStatsFilter{RankMax: Int(10), DownloadsMin: Int(0), SubscribersMax: Int(500)}, url.Values{
+			"popularity_rank_position-max": {"10"},
+			"downloads_total-min":          {"0"},
+			"subscribers_total-max":        {"500"},
+		}},
+		{"paging", ModStatsFilter{Sort: "-downloads_total", Limit: 20, Offset: 40}, url.Values{
+			"_sort":   {"-downloads_total"},
+			"_limit":  {"20"},
+			"_offset": {"40"},
+		}},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.values(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: values = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGetAllModsStats(t *testing.T) {
+	var offsets []string
+	user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		offsets = append(offsets, q.Get("_offset"))
+		if q.Get("_limit") != "2" || q.Get("_sort") != "-downloads_total" {
+			t.Errorf("query = %v", q)
+		}
+		offset, _ := strconv.Atoi(q.Get("_offset"))
+		body := `{"data":[{"mod_id":` + strconv.Itoa(offset+1) + `},{"mod_id":` + strconv.Itoa(offset+2) + `}],"result_total":5}`
+		if offset == 4 {
+			body = `{"data":[{"mod_id":5}],"result_total":5}`
+		}
+		w.Write([]byte(body))
+	})
+	stats, err := user.GetAllModsStats(1, &ModStatsFilter{Sort: "-downloads_total", Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 5 || stats[4].ModID != 5 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if want := []string{"", "2", "4"}; !reflect.DeepEqual(offsets, want) {
+		t.Errorf("offsets = %q, want %q", offsets, want)
+	}
+}
+
+func TestStatsUndecodableErrorBody(t *testing.T) {
+	user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>Bad Gateway</html>"))
+	})
+	if _, err := user.GetGameStats(1); err == nil {
+		t.Error("GetGameStats returned no error")
+	}
+	if _, err := user.GetModStats(2, 1); err == nil {
+		t.Error("GetModStats returned no error")
+	}
+	if _, err := user.GetModsStats(1, nil); err == nil {
+		t.Error("GetModsStats returned no error")
+	}
+	if _, err := user.GetAllModsStats(1, nil); err == nil {
+		t.Error("GetAllModsStats returned no error")
+	}
+}
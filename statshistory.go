@@ -0,0 +1,282 @@
+package gomodio
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// StatsSnapshot is the stats of a game and its mods at one point in time
+type StatsSnapshot struct {
+	Date   Timestamp  `json:"date"`
+	GameID int        `json:"game_id"`
+	Game   *GameStats `json:"game,omitempty"`
+	Mods   []Stats    `json:"mods,omitempty"`
+}
+
+// StatsHistory is an append-only file of stats snapshots, one JSON object per line
+type StatsHistory struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewStatsHistory returns a StatsHistory stored at path. The file is created on the first Append
+func NewStatsHistory(path string) *StatsHistory {
+	return &StatsHistory{path: path}
+}
+
+// Append adds a snapshot to the end of the history
+func (h *StatsHistory) Append(s *StatsSnapshot) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(b, '\n'))
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load reads the snapshots of a game in the order they were recorded. A gameID of 0 loads every game
+func (h *StatsHistory) Load(gameID int) ([]StatsSnapshot, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	f, err := os.Open(h.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var snapshots []StatsSnapshot
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var s StatsSnapshot
+		err = json.Unmarshal(line, &s)
+		if err != nil {
+			return nil, err
+		}
+		if gameID == 0 || s.GameID == gameID {
+			snapshots = append(snapshots, s)
+		}
+	}
+	return snapshots, scanner.Err()
+}
+
+// StatsRecorder snapshots a game's stats into a StatsHistory. Stats are only
+// fetched again once the previous ones have expired
+type StatsRecorder struct {
+	user    *User
+	gameID  int
+	history *StatsHistory
+	// Filter limits which mods' stats are recorded. Nil records every mod
+	Filter  *ModStatsFilter
+	expires time.Time
+}
+
+// NewStatsRecorder returns a recorder saving the stats of gameID into history
+func NewStatsRecorder(user *User, gameID int, history *StatsHistory) *StatsRecorder {
+	return &StatsRecorder{user: user, gameID: gameID, history: history}
+}
+
+// Record fetches and stores a snapshot, returning nil when the last snapshot has not expired yet
+func (r *StatsRecorder) Record() (*StatsSnapshot, error) {
+	if time.Now().Before(r.expires) {
+		return nil, nil
+	}
+	game, err := r.user.GetGameStats(r.gameID)
+	if err != nil {
+		return nil, err
+	}
+	mods, err := r.user.GetAllModsStats(r.gameID, r.Filter)
+	if err != nil {
+		return nil, err
+	}
+	s := &StatsSnapshot{
+		Date:   NewTimestamp(time.Now()),
+		GameID: r.gameID,
+		Game:   game,
+		Mods:   mods,
+	}
+	err = r.history.Append(s)
+	if err != nil {
+		return nil, err
+	}
+	expires := game.DateExpires
+	for _, m := range mods {
+		if !m.DateExpires.IsZero() && (expires.IsZero() || m.DateExpires < expires) {
+			expires = m.DateExpires
+		}
+	}
+	r.expires = expires.Time()
+	return s, nil
+}
+
+// Run calls Record every interval until ctx is done. Record errors are passed to
+// onError, when not nil, and recording carries on
+func (r *StatsRecorder) Run(ctx context.Context, interval time.Duration, onError func(error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		_, err := r.Record()
+		if err != nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ModTrend is how a mod's stats changed between its first and last snapshot
+type ModTrend struct {
+	ModID             int       `json:"mod_id"`
+	From              Timestamp `json:"from"`
+	To                Timestamp `json:"to"`
+	Downloads         int       `json:"downloads"`
+	Subscribers       int       `json:"subscribers"`
+	DownloadsPerDay   float64   `json:"downloads_per_day"`
+	SubscribersPerDay float64   `json:"subscribers_per_day"`
+	RankStart         int       `json:"rank_start"`
+	RankEnd           int       `json:"rank_end"`
+	// RankChange is positive when the mod moved up the popularity ranking
+	RankChange int `json:"rank_change"`
+}
+
+// ModTrends computes each mod's trend over the snapshots taken at or after since,
+// ordered by downloads gained. A zero since uses every snapshot
+func ModTrends(snapshots []StatsSnapshot, since time.Time) []ModTrend {
+	type observation struct {
+		date  Timestamp
+		stats Stats
+	}
+	first := map[int]observation{}
+	last := map[int]observation{}
+	for _, s := range snapshots {
+		if !since.IsZero() && s.Date.Time().Before(since) {
+			continue
+		}
+		for _, m := range s.Mods {
+			o := observation{s.Date, m}
+			if f, ok := first[m.ModID]; !ok || o.date < f.date {
+				first[m.ModID] = o
+			}
+			if l, ok := last[m.ModID]; !ok || o.date >= l.date {
+				last[m.ModID] = o
+			}
+		}
+	}
+	trends := make([]ModTrend, 0, len(first))
+	for id, f := range first {
+		l := last[id]
+		t := ModTrend{
+			ModID:       id,
+			From:        f.date,
+			To:          l.date,
+			Downloads:   l.stats.DownloadsTotal - f.stats.DownloadsTotal,
+			Subscribers: l.stats.SubscribersTotal - f.stats.SubscribersTotal,
+			RankStart:   f.stats.PopularityRankPosition,
+			RankEnd:     l.stats.PopularityRankPosition,
+			RankChange:  f.stats.PopularityRankPosition - l.stats.PopularityRankPosition,
+		}
+		days := l.date.Time().Sub(f.date.Time()).Hours() / 24
+		if days > 0 {
+			t.DownloadsPerDay = float64(t.Downloads) / days
+			t.SubscribersPerDay = float64(t.Subscribers) / days
+		}
+		trends = append(trends, t)
+	}
+	sort.Slice(trends, func(i, j int) bool {
+		if trends[i].Downloads != trends[j].Downloads {
+			return trends[i].Downloads > trends[j].Downloads
+		}
+		return trends[i].ModID < trends[j].ModID
+	})
+	return trends
+}
+
+// WriteTrendsJSON writes trends as a JSON array
+func WriteTrendsJSON(w io.Writer, trends []ModTrend) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(trends)
+}
+
+// WriteTrendsCSV writes trends as CSV with a header row
+func WriteTrendsCSV(w io.Writer, trends []ModTrend) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"mod_id", "from", "to", "downloads", "subscribers", "downloads_per_day", "subscribers_per_day", "rank_start", "rank_end", "rank_change"})
+	if err != nil {
+		return err
+	}
+	for _, t := range trends {
+		err = cw.Write([]string{
+			strconv.Itoa(t.ModID),
+			t.From.String(),
+			t.To.String(),
+			strconv.Itoa(t.Downloads),
+			strconv.Itoa(t.Subscribers),
+			strconv.FormatFloat(t.DownloadsPerDay, 'f', 2, 64),
+			strconv.FormatFloat(t.SubscribersPerDay, 'f', 2, 64),
+			strconv.Itoa(t.RankStart),
+			strconv.Itoa(t.RankEnd),
+			strconv.Itoa(t.RankChange),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteStatsSeriesCSV writes one row per mod per snapshot, for charting stats over time
+func WriteStatsSeriesCSV(w io.Writer, snapshots []StatsSnapshot) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"date", "game_id", "mod_id", "downloads_total", "subscribers_total", "popularity_rank_position", "ratings_positive", "ratings_negative"})
+	if err != nil {
+		return err
+	}
+	for _, s := range snapshots {
+		for _, m := range s.Mods {
+			err = cw.Write([]string{
+				s.Date.String(),
+				strconv.Itoa(s.GameID),
+				strconv.Itoa(m.ModID),
+				strconv.Itoa(m.DownloadsTotal),
+				strconv.Itoa(m.SubscribersTotal),
+				strconv.Itoa(m.PopularityRankPosition),
+				strconv.Itoa(m.RatingsPositive),
+				strconv.Itoa(m.RatingsNegative),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,175 @@
+package gomodio
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const day = 24 * 60 * 60
+
+func trendSnapshots() []StatsSnapshot {
+	return []StatsSnapshot{
+		{Date: 1700000000, GameID: 1, Mods: []Stats{
+			{ModID: 1, DownloadsTotal: 100, SubscribersTotal: 10, PopularityRankPosition: 5},
+			{ModID: 2, DownloadsTotal: 50, SubscribersTotal: 40, PopularityRankPosition: 2},
+		}},
+		{Date: 1700000000 + day, GameID: 1, Mods: []Stats{
+			{ModID: 1, DownloadsTotal: 150, SubscribersTotal: 12, PopularityRankPosition: 3},
+			{ModID: 2, DownloadsTotal: 60, SubscribersTotal: 41, PopularityRankPosition: 2},
+		}},
+		{Date: 1700000000 + 2*day, GameID: 1, Mods: []Stats{
+			{ModID: 1, DownloadsTotal: 300, SubscribersTotal: 20, PopularityRankPosition: 1},
+			{ModID: 2, DownloadsTotal: 70, SubscribersTotal: 38, PopularityRankPosition: 4},
+		}},
+	}
+}
+
+func TestStatsHistoryAppendLoad(t *testing.T) {
+	h := NewStatsHistory(filepath.Join(t.TempDir(), "stats.jsonl"))
+	if s, err := h.Load(0); err != nil || s != nil {
+		t.Fatalf("Load of a missing file = %v, %v", s, err)
+	}
+	for _, s := range trendSnapshots() {
+		s := s
+		if err := h.Append(&s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.Append(&StatsSnapshot{Date: 1700000000, GameID: 2}); err != nil {
+		t.Fatal(err)
+	}
+	game1, err := h.Load(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(game1, trendSnapshots()) {
+		t.Errorf("Load(1) = %+v", game1)
+	}
+	all, err := h.Load(0)
+	if err != nil || len(all) != 4 {
+		t.Errorf("Load(0) = %d snapshots, %v", len(all), err)
+	}
+}
+
+func TestModTrends(t *testing.T) {
+	trends := ModTrends(trendSnapshots(), time.Time{})
+	want := []ModTrend{
+		{ModID: 1, From: 1700000000, To: 1700000000 + 2*day, Downloads: 200, Subscribers: 10,
+			DownloadsPerDay: 100, SubscribersPerDay: 5, RankStart: 5, RankEnd: 1, RankChange: 4},
+		{ModID: 2, From: 1700000000, To: 1700000000 + 2*day, Downloads: 20, Subscribers: -2,
+			DownloadsPerDay: 10, SubscribersPerDay: -1, RankStart: 2, RankEnd: 4, RankChange: -2},
+	}
+	if !reflect.DeepEqual(trends, want) {
+		t.Errorf("ModTrends = %+v, want %+v", trends, want)
+	}
+	since := ModTrends(trendSnapshots(), time.Unix(1700000000+day, 0))
+	if len(since) != 2 || since[0].Downloads != 150 || since[0].From != 1700000000+day {
+		t.Errorf("ModTrends since day 1 = %+v", since)
+	}
+	single := ModTrends(trendSnapshots()[:1], time.Time{})
+	if len(single) != 2 || single[0].Downloads != 0 || single[0].DownloadsPerDay != 0 {
+		t.Errorf("ModTrends of one snapshot = %+v", single)
+	}
+}
+
+func TestWriteTrendsCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteTrendsCSV(&buf, ModTrends(trendSnapshots(), time.Time{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "mod_id,from,to,downloads,subscribers,downloads_per_day,subscribers_per_day,rank_start,rank_end,rank_change\n" +
+		"1,2023-11-14T22:13:20Z,2023-11-16T22:13:20Z,200,10,100.00,5.00,5,1,4\n" +
+		"2,2023-11-14T22:13:20Z,2023-11-16T22:13:20Z,20,-2,10.00,-1.00,2,4,-2\n"
+	if buf.String() != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteStatsSeriesCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteStatsSeriesCSV(&buf, trendSnapshots()[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "date,game_id,mod_id,downloads_total,subscribers_total,popularity_rank_position,ratings_positive,ratings_negative\n" +
+		"2023-11-14T22:13:20Z,1,1,100,10,5,0,0\n" +
+		"2023-11-14T22:13:20Z,1,2,50,40,2,0,0\n"
+	if buf.String() != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWriteCSVErrors(t *testing.T) {
+	// Enough rows to fill the csv.Writer's buffer before the final flush
+	var trends []ModTrend
+	snapshot := StatsSnapshot{Date: 1700000000, GameID: 1}
+	for i := 0; i < 500; i++ {
+		trends = append(trends, ModTrend{ModID: i})
+		snapshot.Mods = append(snapshot.Mods, Stats{ModID: i})
+	}
+	if err := WriteTrendsCSV(failingWriter{}, trends); err == nil {
+		t.Error("WriteTrendsCSV returned no error")
+	}
+	if err := WriteStatsSeriesCSV(failingWriter{}, []StatsSnapshot{snapshot}); err == nil {
+		t.Error("WriteStatsSeriesCSV returned no error")
+	}
+}
+
+func TestStatsRecorderExpiry(t *testing.T) {
+	requests := 0
+	gameExpires := time.Now().Add(time.Hour).Unix()
+	modExpires := time.Now().Add(-time.Minute).Unix()
+	user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if strings.HasSuffix(r.URL.Path, "/mods/stats") {
+			w.Write([]byte(`{"data":[{"mod_id":2,"date_expires":` + strconv.FormatInt(modExpires, 10) + `}],"result_total":1}`))
+			return
+		}
+		w.Write([]byte(`{"game_id":1,"date_expires":` + strconv.FormatInt(gameExpires, 10) + `}`))
+	})
+	history := NewStatsHistory(filepath.Join(t.TempDir(), "stats.jsonl"))
+	r := NewStatsRecorder(user, 1, history)
+
+	s, err := r.Record()
+	if err != nil || s == nil {
+		t.Fatalf("first Record = %v, %v", s, err)
+	}
+	// The mod's stats expired already, so the earliest expiry forces a new snapshot
+	s, err = r.Record()
+	if err != nil || s == nil {
+		t.Fatalf("Record after expiry = %v, %v", s, err)
+	}
+	modExpires = time.Now().Add(30 * time.Minute).Unix()
+	if _, err := r.Record(); err != nil {
+		t.Fatal(err)
+	}
+	before := requests
+	s, err = r.Record()
+	if err != nil || s != nil {
+		t.Errorf("Record before expiry = %v, %v, want nil", s, err)
+	}
+	if requests != before {
+		t.Errorf("Record before expiry sent %d requests", requests-before)
+	}
+	if want := time.Unix(modExpires, 0); !r.expires.Equal(want) {
+		t.Errorf("expires = %v, want the mod's %v", r.expires, want)
+	}
+	snapshots, err := history.Load(1)
+	if err != nil || len(snapshots) != 3 {
+		t.Errorf("history has %d snapshots, %v", len(snapshots), err)
+	}
+}
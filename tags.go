@@ -12,33 +12,49 @@ import (
 	"time"
 )
 
-// Tag struct represents the tag object from mod.io
-type Tag struct {
-	Name      string `json:"name"`
-	DateAdded int    `json:"date_added"`
+// ModTag struct represents the tag object from mod.io
+type ModTag struct {
+	Name      string    `json:"name"`
+	DateAdded Timestamp `json:"date_added"`
 }
 
+// Tag is the previous name of ModTag
+type Tag = ModTag
+
 // Tags struct is a collection of Tags
 type Tags struct {
-	Data         []Tag `json:"data"`
-	ResultCount  int   `json:"result_count"`
-	ResultLimit  int   `json:"result_limit"`
-	ResultTotal  int   `json:"result_total"`
-	ResultOffset int   `json:"result_offset"`
+	Data         []ModTag `json:"data"`
+	ResultCount  int      `json:"result_count"`
+	ResultLimit  int      `json:"result_limit"`
+	ResultTotal  int      `json:"result_total"`
+	ResultOffset int      `json:"result_offset"`
 }
 
-// GameTags struct is a game's tags object
-type GameTags struct {
+// TagOption struct is a game's tag group object
+type TagOption struct {
 	Name   string   `json:"name"`
 	Type   string   `json:"type"`
-	Tags   []string `json:"string"`
-	Hidden bool     `json:"bool"`
+	Tags   []string `json:"tags"`
+	Hidden bool     `json:"hidden"`
+}
+
+// GameTags is the previous name of TagOption
+type GameTags = TagOption
+
+// TagOptions struct is a collection of a game's TagOptions
+type TagOptions struct {
+	Data         []TagOption `json:"data"`
+	ResultCount  int         `json:"result_count"`
+	ResultLimit  int         `json:"result_limit"`
+	ResultTotal  int         `json:"result_total"`
+	ResultOffset int         `json:"result_offset"`
 }
 
 // DeleteGameTagOption deletes a game tag option
 func (user *User) DeleteGameTagOption(tagGroupName string, tags []string, gameID int) (err error) {
-	if user.OAuth2Token() == "" {
-		return errors.New("requires oauth2 authentication")
+	err = user.requireToken()
+	if err != nil {
+		return err
 	}
 	var queryBody url.Values
 	options := map[string]string{
@@ -47,15 +63,11 @@ func (user *User) DeleteGameTagOption(tagGroupName string, tags []string, gameID
 	}
 	queryBody = ParseArgsBody(options)
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("DELETE", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/tags", strings.NewReader(queryBody.Encode()))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequest("DELETE", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/tags", strings.NewReader(queryBody.Encode()))
 	if err != nil {
 		return err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return err
 	}
@@ -77,8 +89,9 @@ func (user *User) DeleteGameTagOption(tagGroupName string, tags []string, gameID
 
 // AddGameTagOption adds a single option to game tags
 func (user *User) AddGameTagOption(tagGroupName, tagGroupType string, tags []string, gameID int, options map[string]string) (m *Message, err error) {
-	if user.OAuth2Token() == "" {
-		return nil, errors.New("requires oauth2 authentication")
+	err = user.requireToken()
+	if err != nil {
+		return nil, err
 	}
 	var queryBody url.Values
 	if tagGroupType != "dropdown" {
@@ -101,15 +114,11 @@ func (user *User) AddGameTagOption(tagGroupName, tagGroupType string, tags []str
 		queryBody.Add("tags", "[\""+strings.Join(tags, "\",\"")+"\"]")
 	}
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/tags", nil)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/tags?api_key="+user.APIKey(), nil)
 	if err != nil {
 		return nil, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return nil, err
 	}
@@ -134,17 +143,13 @@ func (user *User) AddGameTagOption(tagGroupName, tagGroupType string, tags []str
 }
 
 // GetGameTagOptions gets a game's tag options
-func (user *User) GetGameTagOptions(gameID int) (t *Tags, err error) {
+func (user *User) GetGameTagOptions(gameID int) (t *TagOptions, err error) {
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/tags", nil)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/tags?api_key="+user.APIKey(), nil)
 	if err != nil {
 		return nil, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return nil, err
 	}
@@ -170,21 +175,20 @@ func (user *User) GetGameTagOptions(gameID int) (t *Tags, err error) {
 
 // DeleteModTags deletes a tag from a mod. Requires OAuth2
 func (user *User) DeleteModTags(tags []string, modID, gameID int) (err error) {
-	var queryBody url.Values
-	if user.OAuth2Token() == "" {
-		return errors.New("requires oauth2 authentication")
+	queryBody := url.Values{}
+	err = user.requireToken()
+	if err != nil {
+		return err
+	}
+	for _, t := range tags {
+		queryBody.Add("tags[]", t)
 	}
-	queryBody.Add("tags", "[\""+strings.Join(tags, "\",\"")+"\"]")
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("DELETE", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/tags", strings.NewReader(queryBody.Encode()))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequest("DELETE", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/tags", strings.NewReader(queryBody.Encode()))
 	if err != nil {
 		return err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return err
 	}
@@ -206,23 +210,20 @@ func (user *User) DeleteModTags(tags []string, modID, gameID int) (err error) {
 
 // AddModTags adds a tag to a mod. Requires OAuth2
 func (user *User) AddModTags(tags []string, modID, gameID int) (t *Message, err error) {
-	var queryBody url.Values
-	if user.OAuth2Token() == "" {
-		return nil, errors.New("requires oauth2 authentication")
+	queryBody := url.Values{}
+	err = user.requireToken()
+	if err != nil {
+		return nil, err
 	}
 	for _, t := range tags {
-		queryBody.Add("tags", t)
+		queryBody.Add("tags[]", t)
 	}
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/tags", strings.NewReader(queryBody.Encode()))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequest("POST", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/tags", strings.NewReader(queryBody.Encode()))
 	if err != nil {
 		return nil, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return nil, err
 	}
@@ -257,15 +258,11 @@ func (user *User) GetModTags(modID, gameID int, options map[string]string) (t *T
 		queryStr = "api_key=" + user.APIKey()
 	}
 	client := http.Client{Timeout: time.Duration(5 * time.Second)}
-	req, err := http.NewRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/tags?"+queryStr, nil)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := user.newRequest("GET", "https://api.mod.io/v1/games/"+strconv.Itoa(gameID)+"/mods/"+strconv.Itoa(modID)+"/tags?"+queryStr, nil)
 	if err != nil {
 		return nil, err
 	}
-	if user.OAuth2Token() != "" || len(user.OAuth2Token()) > 1 {
-		req.Header.Set("Authorization", "Bearer "+user.OAuth2Token())
-	}
-	resp, err := client.Do(req)
+	resp, err := user.do(&client, req)
 	if err != nil {
 		return nil, err
 	}
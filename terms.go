@@ -0,0 +1,81 @@
+package gomodio
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Terms struct maps to the JSON response of Get Terms. Render it as a consent
+// dialog before third-party authentication and set ExternalAuthOptions.TermsAgreed
+// once the player picks the agree button
+type Terms struct {
+	Plaintext string `json:"plaintext"`
+	HTML      string `json:"html"`
+	Buttons   struct {
+		Agree    TermsButton `json:"agree"`
+		Disagree TermsButton `json:"disagree"`
+	} `json:"buttons"`
+	Links struct {
+		Website TermsLink `json:"website"`
+		Terms   TermsLink `json:"terms"`
+		Privacy TermsLink `json:"privacy"`
+		Manage  TermsLink `json:"manage"`
+	} `json:"links"`
+}
+
+// TermsButton is the label of a button in the terms dialog
+type TermsButton struct {
+	Text string `json:"text"`
+}
+
+// TermsLink is a link shown in the terms dialog. Required links must be displayed
+type TermsLink struct {
+	Text     string `json:"text"`
+	URL      string `json:"url"`
+	Required bool   `json:"required"`
+}
+
+// LinkList returns the terms' links in display order, skipping empty ones
+func (t *Terms) LinkList() []TermsLink {
+	var links []TermsLink
+	for _, l := range []TermsLink{t.Links.Website, t.Links.Terms, t.Links.Privacy, t.Links.Manage} {
+		if l.URL != "" {
+			links = append(links, l)
+		}
+	}
+	return links
+}
+
+// GetTerms gets the terms of use a player must accept before third-party authentication
+func (u *User) GetTerms(ctx context.Context) (t *Terms, err error) {
+	client := http.Client{Timeout: time.Duration(5 * time.Second)}
+	req, err := u.newRequestWithContext(ctx, "GET", "https://api.mod.io/v1/authenticate/terms?api_key="+u.APIKey(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := u.do(&client, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		var errObj ErrorCase
+		e := json.Unmarshal(body, &errObj)
+		if e != nil {
+			return nil, e
+		}
+		return nil, HandleResponseError(errObj)
+	}
+	err = json.Unmarshal(body, &t)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
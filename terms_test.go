@@ -0,0 +1,63 @@
+package gomodio
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGetTerms(t *testing.T) {
+	user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/authenticate/terms" || r.URL.Query().Get("api_key") != "key" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{
+			"plaintext": "Agree to the terms",
+			"html": "<p>Agree to the terms</p>",
+			"buttons": {"agree": {"text": "I Agree"}, "disagree": {"text": "No, Thanks"}},
+			"links": {
+				"website": {"text": "Website", "url": "https://mod.io", "required": false},
+				"terms": {"text": "Terms of Use", "url": "https://mod.io/terms", "required": true},
+				"privacy": {"text": "Privacy Policy", "url": "https://mod.io/privacy", "required": true},
+				"manage": {"text": "Manage", "url": "", "required": false}
+			}
+		}`))
+	})
+	terms, err := user.GetTerms(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if terms.Plaintext != "Agree to the terms" || terms.HTML != "<p>Agree to the terms</p>" {
+		t.Errorf("text = %q, %q", terms.Plaintext, terms.HTML)
+	}
+	if terms.Buttons.Agree.Text != "I Agree" || terms.Buttons.Disagree.Text != "No, Thanks" {
+		t.Errorf("buttons = %+v", terms.Buttons)
+	}
+	want := []TermsLink{
+		{Text: "Website", URL: "https://mod.io"},
+		{Text: "Terms of Use", URL: "https://mod.io/terms", Required: true},
+		{Text: "Privacy Policy", URL: "https://mod.io/privacy", Required: true},
+	}
+	if got := terms.LinkList(); !reflect.DeepEqual(got, want) {
+		t.Errorf("LinkList = %+v, want %+v", got, want)
+	}
+}
+
+func TestExternalAuthTermsAgreed(t *testing.T) {
+	for _, agreed := range []bool{false, true} {
+		var sent string
+		user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+			r.ParseForm()
+			sent = r.PostForm.Get("terms_agreed")
+			w.Write([]byte(`{"code":200,"access_token":"token"}`))
+		})
+		_, err := user.SteamAuth(context.Background(), "ticket", ExternalAuthOptions{TermsAgreed: agreed})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := map[bool]string{false: "", true: "true"}[agreed]; sent != want {
+			t.Errorf("TermsAgreed %v sent terms_agreed=%q, want %q", agreed, sent, want)
+		}
+	}
+}
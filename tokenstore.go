@@ -0,0 +1,218 @@
+package gomodio
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// ErrTokenExpired is returned when an authenticated call is made with an expired OAuth2 token
+var ErrTokenExpired = errors.New("OAuth2 token has expired")
+
+// Token is a persisted OAuth2 token and its expiry
+type Token struct {
+	AccessToken string    `json:"access_token"`
+	DateExpires Timestamp `json:"date_expires"`
+}
+
+// Expired reports whether the token has a known expiry in the past
+func (t *Token) Expired() bool {
+	return t.DateExpires.Expired()
+}
+
+// TokenStore persists OAuth2 tokens per email and game.
+// LoadToken returns a nil Token and nil error when nothing is stored
+type TokenStore interface {
+	LoadToken(email string, gameID int) (*Token, error)
+	SaveToken(email string, gameID int, t *Token) error
+	DeleteToken(email string, gameID int) error
+}
+
+func tokenKey(email string, gameID int) string {
+	return strconv.Itoa(gameID) + "/" + email
+}
+
+// MemoryTokenStore keeps tokens in memory for the life of the process
+type MemoryTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]Token
+}
+
+// NewMemoryTokenStore returns an empty MemoryTokenStore
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{tokens: map[string]Token{}}
+}
+
+// LoadToken returns the stored token for email and gameID
+func (s *MemoryTokenStore) LoadToken(email string, gameID int) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tokens[tokenKey(email, gameID)]
+	if !ok {
+		return nil, nil
+	}
+	return &t, nil
+}
+
+// SaveToken stores the token for email and gameID
+func (s *MemoryTokenStore) SaveToken(email string, gameID int, t *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[tokenKey(email, gameID)] = *t
+	return nil
+}
+
+// DeleteToken removes the token for email and gameID
+func (s *MemoryTokenStore) DeleteToken(email string, gameID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, tokenKey(email, gameID))
+	return nil
+}
+
+// FileTokenStore keeps tokens in a single JSON file readable only by its owner.
+// When created with a key the file is encrypted at rest with AES-GCM
+type FileTokenStore struct {
+	mu   sync.Mutex
+	path string
+	aead cipher.AEAD
+}
+
+// NewFileTokenStore returns a FileTokenStore writing plain JSON to path
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+// NewEncryptedFileTokenStore returns a FileTokenStore encrypting path with AES-GCM.
+// key must be 16, 24 or 32 random bytes, such as one made by NewTokenStoreKey and
+// kept in the platform's secret storage. Passphrases are not accepted
+func NewEncryptedFileTokenStore(path string, key []byte) (*FileTokenStore, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, errors.New("encryption key must be 16, 24 or 32 random bytes")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &FileTokenStore{path: path, aead: aead}, nil
+}
+
+// NewTokenStoreKey returns a random 32 byte key for NewEncryptedFileTokenStore
+func NewTokenStoreKey() ([]byte, error) {
+	key := make([]byte, 32)
+	_, err := io.ReadFull(rand.Reader, key)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// LoadToken returns the stored token for email and gameID
+func (s *FileTokenStore) LoadToken(email string, gameID int) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	t, ok := tokens[tokenKey(email, gameID)]
+	if !ok {
+		return nil, nil
+	}
+	return &t, nil
+}
+
+// SaveToken stores the token for email and gameID
+func (s *FileTokenStore) SaveToken(email string, gameID int, t *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	tokens[tokenKey(email, gameID)] = *t
+	return s.write(tokens)
+}
+
+// DeleteToken removes the token for email and gameID
+func (s *FileTokenStore) DeleteToken(email string, gameID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	delete(tokens, tokenKey(email, gameID))
+	return s.write(tokens)
+}
+
+func (s *FileTokenStore) read() (map[string]Token, error) {
+	tokens := map[string]Token{}
+	b, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.aead != nil {
+		size := s.aead.NonceSize()
+		if len(b) < size {
+			return nil, errors.New("token store is corrupt")
+		}
+		b, err = s.aead.Open(nil, b[:size], b[size:], nil)
+		if err != nil {
+			return nil, errors.New("token store cannot be decrypted with this key")
+		}
+	}
+	err = json.Unmarshal(b, &tokens)
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (s *FileTokenStore) write(tokens map[string]Token) error {
+	b, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	if s.aead != nil {
+		nonce := make([]byte, s.aead.NonceSize())
+		_, err = io.ReadFull(rand.Reader, nonce)
+		if err != nil {
+			return err
+		}
+		b = s.aead.Seal(nonce, nonce, b, nil)
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), ".tokens-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	err = tmp.Chmod(0600)
+	if err == nil {
+		_, err = tmp.Write(b)
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
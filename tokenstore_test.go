@@ -0,0 +1,58 @@
+package gomodio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEncryptedFileTokenStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens")
+	key, err := NewTokenStoreKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewEncryptedFileTokenStore(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Token{AccessToken: "secret", DateExpires: NewTimestamp(time.Now().Add(time.Hour))}
+	err = store.SaveToken("player@example.com", 1, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("file mode = %v, want 0600", mode)
+	}
+
+	got, err := store.LoadToken("player@example.com", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || *got != *want {
+		t.Errorf("LoadToken = %+v, want %+v", got, want)
+	}
+
+	wrongKey, _ := NewTokenStoreKey()
+	other, err := NewEncryptedFileTokenStore(path, wrongKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.LoadToken("player@example.com", 1); err == nil {
+		t.Error("LoadToken with the wrong key succeeded")
+	}
+}
+
+func TestEncryptedFileTokenStoreRejectsPassphrase(t *testing.T) {
+	for _, key := range [][]byte{nil, []byte("hunter2"), make([]byte, 31)} {
+		if _, err := NewEncryptedFileTokenStore("tokens", key); err == nil {
+			t.Errorf("key of %d bytes accepted", len(key))
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package gomodio
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Timestamp is a Unix timestamp in seconds as sent by mod.io.
+// It decodes from and encodes back to the same JSON number
+type Timestamp int64
+
+// NewTimestamp returns the Timestamp of t, or zero for the zero time
+func NewTimestamp(t time.Time) Timestamp {
+	if t.IsZero() {
+		return 0
+	}
+	return Timestamp(t.Unix())
+}
+
+// Time returns the Timestamp as a time.Time. A zero Timestamp returns the zero time
+func (t Timestamp) Time() time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(t), 0)
+}
+
+// IsZero reports whether the Timestamp is unset
+func (t Timestamp) IsZero() bool {
+	return t == 0
+}
+
+// Expired reports whether the Timestamp is set and in the past
+func (t Timestamp) Expired() bool {
+	return t != 0 && time.Now().Unix() >= int64(t)
+}
+
+// String returns the Timestamp formatted as RFC 3339, or "" when unset
+func (t Timestamp) String() string {
+	if t == 0 {
+		return ""
+	}
+	return t.Time().UTC().Format(time.RFC3339)
+}
+
+// UnmarshalJSON decodes a JSON number. null leaves the Timestamp unchanged
+func (t *Timestamp) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+	var n json.Number
+	err := json.Unmarshal(b, &n)
+	if err != nil {
+		return err
+	}
+	i, err := n.Int64()
+	if err != nil {
+		f, ferr := n.Float64()
+		if ferr != nil {
+			return err
+		}
+		i = int64(math.Floor(f))
+	}
+	*t = Timestamp(i)
+	return nil
+}
+
+// MarshalJSON encodes the Timestamp as a JSON number of Unix seconds
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(t), 10)), nil
+}
+
+// UserProfile struct represents a mod.io user object as embedded in mods, games and comments
+type UserProfile struct {
+	ID         int       `json:"id"`
+	NameID     string    `json:"name_id"`
+	Username   string    `json:"username"`
+	DateOnline Timestamp `json:"date_online"`
+	Avatar     Avatar    `json:"avatar"`
+	Timezone   string    `json:"timezone"`
+	Language   string    `json:"language"`
+	ProfileURL string    `json:"profile_url"`
+}
+
+// Avatar struct represents a user's avatar image and its thumbnails
+type Avatar struct {
+	Filename     string `json:"filename"`
+	Original     string `json:"original"`
+	Thumb50X50   string `json:"thumb_50x50"`
+	Thumb100X100 string `json:"thumb_100x100"`
+}
+
+// Logo struct represents a mod or game logo and its thumbnails
+type Logo struct {
+	Filename      string `json:"filename"`
+	Original      string `json:"original"`
+	Thumb320X180  string `json:"thumb_320x180"`
+	Thumb640X360  string `json:"thumb_640x360"`
+	Thumb1280X720 string `json:"thumb_1280x720"`
+}
+
+// Icon struct represents a game icon and its thumbnails
+type Icon struct {
+	Filename     string `json:"filename"`
+	Original     string `json:"original"`
+	Thumb64X64   string `json:"thumb_64x64"`
+	Thumb128X128 string `json:"thumb_128x128"`
+	Thumb256X256 string `json:"thumb_256x256"`
+}
+
+// Header struct represents a game header image
+type Header struct {
+	Filename string `json:"filename"`
+	Original string `json:"original"`
+}
+
+// Image struct represents a mod media image and its thumbnail
+type Image struct {
+	Filename     string `json:"filename"`
+	Original     string `json:"original"`
+	Thumb320X180 string `json:"thumb_320x180"`
+}
@@ -0,0 +1,86 @@
+package gomodio
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimestampUnmarshal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Timestamp
+	}{
+		{`1700000000`, 1700000000},
+		{`0`, 0},
+		{`1700000000.9`, 1700000000},
+		{`1.7e9`, 1700000000},
+		{`"1700000000"`, 1700000000},
+		{`null`, 42},
+	}
+	for _, tt := range tests {
+		ts := Timestamp(42)
+		err := json.Unmarshal([]byte(tt.in), &ts)
+		if err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.in, err)
+			continue
+		}
+		if ts != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, want %d", tt.in, ts, tt.want)
+		}
+	}
+	var ts Timestamp
+	if err := json.Unmarshal([]byte(`"yesterday"`), &ts); err == nil {
+		t.Error("non-numeric timestamp decoded without error")
+	}
+}
+
+func TestTimestampAccessors(t *testing.T) {
+	ts := Timestamp(1700000000)
+	if got := ts.Time(); !got.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Time() = %v", got)
+	}
+	if got := ts.String(); got != "2023-11-14T22:13:20Z" {
+		t.Errorf("String() = %q", got)
+	}
+	if b, _ := json.Marshal(ts); string(b) != "1700000000" {
+		t.Errorf("Marshal = %s", b)
+	}
+	var zero Timestamp
+	if !zero.IsZero() || !zero.Time().IsZero() || zero.String() != "" || zero.Expired() {
+		t.Error("zero Timestamp is not reported as unset")
+	}
+	if NewTimestamp(time.Time{}) != 0 {
+		t.Error("NewTimestamp of the zero time is not zero")
+	}
+	if !NewTimestamp(time.Now().Add(-time.Minute)).Expired() || NewTimestamp(time.Now().Add(time.Minute)).Expired() {
+		t.Error("Expired gave wrong results")
+	}
+}
+
+func TestTimestampDecodeEventAndGame(t *testing.T) {
+	var events Events
+	err := json.Unmarshal([]byte(`{"data":[{"id":1,"mod_id":2,"date_added":1700000000,"event_type":"MODFILE_CHANGED"}]}`), &events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := events.Data[0].DateAdded.Time(); !got.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("event date_added = %v", got)
+	}
+	var game Game
+	err = json.Unmarshal([]byte(`{"id":1,"date_added":1600000000,"date_updated":1650000000,"date_live":0}`), &game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if game.DateAdded != 1600000000 || game.DateUpdated != 1650000000 || !game.DateLive.IsZero() {
+		t.Errorf("game dates = %d, %d, %d", game.DateAdded, game.DateUpdated, game.DateLive)
+	}
+	b, err := json.Marshal(&game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back Game
+	if err := json.Unmarshal(b, &back); err != nil || back.DateUpdated != game.DateUpdated {
+		t.Errorf("round trip date_updated = %d, %v", back.DateUpdated, err)
+	}
+}
@@ -1,9 +1,11 @@
 package gomodio
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -13,20 +15,33 @@ import (
 
 // User Struct for mod.io
 type User struct {
-	apikey      string
-	email       string
-	oauth2token string
+	apikey       string
+	email        string
+	oauth2token  string
+	tokenExpires Timestamp
+	tokenStore   TokenStore
+	tokenGameID  int
+	platform     Platform
+	portal       Portal
+	language     string
+	hooks        []Hook
+	maxRetries   int
+	cache        Cache
+	cacheTTL     time.Duration
+	// transport, when set, replaces the transport of every request's client
+	transport http.RoundTripper
 }
 
 // ExchangeResponse Struct for Response of Email Exchange
 type ExchangeResponse struct {
-	OAuthToken string `json:"access_token"`
-	Code       int    `json:"code"`
+	OAuthToken  string    `json:"access_token"`
+	Code        int       `json:"code"`
+	DateExpires Timestamp `json:"date_expires"`
 }
 
 // NewUser - Initializes a new User
 func NewUser(apikey string, email string) *User {
-	return &User{apikey, email, ""}
+	return &User{apikey: apikey, email: email}
 }
 
 // APIKey returns the User's API key
@@ -50,50 +65,286 @@ func (u *User) SetOAuth2Token(token string) {
 	u.oauth2token = token
 }
 
-// RequestSecurityCode Authenticate with mod.io Using API Key Only
-func (u *User) RequestSecurityCode() bool {
-	requestBody := url.Values{
+// Platform returns the platform the User targets
+func (u *User) Platform() Platform {
+	return u.platform
+}
+
+// SetPlatform sets the platform sent as X-Modio-Platform on every request,
+// so mod.io returns the modfiles approved for that platform
+func (u *User) SetPlatform(platform Platform) {
+	u.platform = platform
+}
+
+// Portal returns the portal the User targets
+func (u *User) Portal() Portal {
+	return u.portal
+}
+
+// SetPortal sets the portal sent as X-Modio-Portal on every request
+func (u *User) SetPortal(portal Portal) {
+	u.portal = portal
+}
+
+// Language returns the language the User requests content in
+func (u *User) Language() string {
+	return u.language
+}
+
+// SetLanguage sets the default language sent as Accept-Language, e.g. "de" or "ja".
+// mod.io returns translated names, summaries and descriptions where they exist
+func (u *User) SetLanguage(language string) {
+	u.language = language
+}
+
+// WithLanguage returns a copy of the User requesting content in language,
+// for overriding the default language of a single call:
+//
+//	mod, err := user.WithLanguage("ja").GetMod(modID, gameID, nil)
+func (u *User) WithLanguage(language string) *User {
+	c := *u
+	c.language = language
+	// Hooks added to the copy must not land in the original's backing array
+	c.hooks = append([]Hook(nil), u.hooks...)
+	return &c
+}
+
+// TokenExpires returns when the User's OAuth2Token expires.
+// It is the zero time when the expiry is unknown
+func (u *User) TokenExpires() time.Time {
+	return u.tokenExpires.Time()
+}
+
+// SetTokenExpires sets when the User's OAuth2Token expires
+func (u *User) SetTokenExpires(expires time.Time) {
+	u.tokenExpires = NewTimestamp(expires)
+}
+
+// TokenExpired reports whether the User's OAuth2Token has a known expiry in the past
+func (u *User) TokenExpired() bool {
+	return u.tokenExpires.Expired()
+}
+
+// UseTokenStore loads the User's token for gameID from store and saves tokens
+// obtained later on to it. ErrTokenExpired is returned when the stored token
+// has expired, in which case the User is left without a token
+func (u *User) UseTokenStore(store TokenStore, gameID int) error {
+	u.tokenStore = store
+	u.tokenGameID = gameID
+	t, err := store.LoadToken(u.Email(), gameID)
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		return nil
+	}
+	if t.Expired() {
+		return ErrTokenExpired
+	}
+	u.oauth2token = t.AccessToken
+	u.tokenExpires = t.DateExpires
+	return nil
+}
+
+// requireToken fails fast when the User has no usable OAuth2Token
+func (u *User) requireToken() error {
+	if u.OAuth2Token() == "" {
+		return errors.New("requires OAuth2 token")
+	}
+	if u.TokenExpired() {
+		return ErrTokenExpired
+	}
+	return nil
+}
+
+// saveToken persists the User's current token when a TokenStore is in use
+func (u *User) saveToken() error {
+	if u.tokenStore == nil {
+		return nil
+	}
+	return u.tokenStore.SaveToken(u.Email(), u.tokenGameID, &Token{AccessToken: u.oauth2token, DateExpires: u.tokenExpires})
+}
+
+// RequestSecurityCode requests a security code be emailed to the User's Email
+func (u *User) RequestSecurityCode(ctx context.Context) (m *Message, err error) {
+	reqBody := url.Values{
 		"api_key": {u.APIKey()},
 		"email":   {u.Email()},
 	}
-	resp, err := http.Post("https://api.mod.io/v1/oauth/emailrequest", "application/x-www-form-urlencoded", strings.NewReader(requestBody.Encode()))
+	client := http.Client{Timeout: time.Duration(5 * time.Second)}
+	req, err := u.newRequestWithContext(ctx, "POST", "https://api.mod.io/v1/oauth/emailrequest", strings.NewReader(reqBody.Encode()))
 	if err != nil {
-		log.Fatalln(err)
+		return nil, err
+	}
+	resp, err := u.do(&client, req)
+	if err != nil {
+		return nil, err
 	}
 	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
 	if resp.StatusCode != 200 {
-		log.Fatalln("Failed To Send Email Request. Check E-Mail/API Key")
-	} else {
-		return true
+		var errObj ErrorCase
+		e := json.Unmarshal(body, &errObj)
+		if e != nil {
+			return nil, e
+		}
+		return nil, HandleResponseError(errObj)
+	}
+	err = json.Unmarshal(body, &m)
+	if err != nil {
+		return nil, err
 	}
-	return false
+	return m, nil
 }
 
-// ExchangeSecurityCode Function
-func (u *User) ExchangeSecurityCode(securitycode string) *User {
+// ExchangeSecurityCode exchanges an emailed security code for an OAuth2 token
+// valid for lifetime. A zero lifetime uses mod.io's default of one year
+func (u *User) ExchangeSecurityCode(ctx context.Context, securitycode string, lifetime time.Duration) (*User, error) {
 	reqBody := url.Values{
 		"api_key":       {u.APIKey()},
 		"security_code": {securitycode},
-		"date_expires":  {strconv.FormatInt(time.Now().Unix()+31536000, 10)},
 	}
-	resp, err := http.Post("https://api.mod.io/v1/oauth/emailexchange", "application/x-www-form-urlencoded", strings.NewReader(reqBody.Encode()))
+	if lifetime > 0 {
+		reqBody.Set("date_expires", strconv.FormatInt(time.Now().Add(lifetime).Unix(), 10))
+	}
+	client := http.Client{Timeout: time.Duration(5 * time.Second)}
+	req, err := u.newRequestWithContext(ctx, "POST", "https://api.mod.io/v1/oauth/emailexchange", strings.NewReader(reqBody.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := u.do(&client, req)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, err
 	}
 	defer resp.Body.Close()
-	var res ExchangeResponse
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, err
 	}
+	if resp.StatusCode != 200 {
+		var errObj ErrorCase
+		e := json.Unmarshal(body, &errObj)
+		if e != nil {
+			return nil, e
+		}
+		return nil, HandleResponseError(errObj)
+	}
+	var res ExchangeResponse
 	err = json.Unmarshal(body, &res)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, err
+	}
+	u.SetOAuth2Token(res.OAuthToken)
+	u.tokenExpires = res.DateExpires
+	err = u.saveToken()
+	if err != nil {
+		return u, err
+	}
+	return u, nil
+}
+
+// Logout revokes the User's OAuth2 token on mod.io and forgets it locally
+func (u *User) Logout(ctx context.Context) (err error) {
+	err = u.requireToken()
+	if err != nil {
+		return err
+	}
+	client := http.Client{Timeout: time.Duration(5 * time.Second)}
+	req, err := u.newRequestWithContext(ctx, "POST", "https://api.mod.io/v1/oauth/logout", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := u.do(&client, req)
+	if err != nil {
+		return err
 	}
-	if res.Code != 200 {
-		log.Fatalln("Incorrect Security Code Provided")
-	} else {
-		u.SetOAuth2Token(res.OAuthToken)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 && resp.StatusCode != 204 {
+		var errObj ErrorCase
+		e := json.Unmarshal(body, &errObj)
+		if e != nil {
+			return e
+		}
+		return HandleResponseError(errObj)
+	}
+	u.oauth2token = ""
+	u.tokenExpires = 0
+	if u.tokenStore != nil {
+		return u.tokenStore.DeleteToken(u.Email(), u.tokenGameID)
+	}
+	return nil
+}
+
+// newRequest builds a request to mod.io with the headers every call shares
+func (u *User) newRequest(method, url string, body io.Reader) (*http.Request, error) {
+	return u.newRequestWithContext(context.Background(), method, url, body)
+}
+
+// newRequestWithContext is newRequest bound to ctx
+func (u *User) newRequestWithContext(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if u.OAuth2Token() != "" {
+		req.Header.Set("Authorization", "Bearer "+u.OAuth2Token())
+	}
+	if u.platform != "" {
+		req.Header.Set("X-Modio-Platform", string(u.platform))
+	}
+	if u.portal != "" {
+		req.Header.Set("X-Modio-Portal", string(u.portal))
+	}
+	if u.language != "" {
+		req.Header.Set("Accept-Language", u.language)
+	}
+	return req, nil
+}
+
+// GetAuthenticatedUser gets the profile of the user the OAuth2 token belongs to
+func (u *User) GetAuthenticatedUser() (p *UserProfile, err error) {
+	return u.getAuthenticatedUser(context.Background())
+}
+
+// getAuthenticatedUser is GetAuthenticatedUser bound to ctx
+func (u *User) getAuthenticatedUser(ctx context.Context) (p *UserProfile, err error) {
+	err = u.requireToken()
+	if err != nil {
+		return nil, err
+	}
+	client := http.Client{Timeout: time.Duration(5 * time.Second)}
+	req, err := u.newRequestWithContext(ctx, "GET", "https://api.mod.io/v1/me?api_key="+u.APIKey(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := u.do(&client, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		var errObj ErrorCase
+		e := json.Unmarshal(body, &errObj)
+		if e != nil {
+			return nil, e
+		}
+		return nil, HandleResponseError(errObj)
+	}
+	err = json.Unmarshal(body, &p)
+	if err != nil {
+		return nil, err
 	}
-	return u
+	return p, nil
 }
@@ -0,0 +1,239 @@
+package gomodio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// roundTripFunc lets a function act as a User's transport
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newTestUser returns a User whose requests to mod.io are served by handler
+func newTestUser(t *testing.T, handler http.HandlerFunc) *User {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	target, _ := url.Parse(srv.URL)
+	user := NewUser("key", "player@example.com")
+	user.transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		req = req.Clone(req.Context())
+		req.URL.Scheme = target.Scheme
+		req.URL.Host = target.Host
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	return user
+}
+
+func TestRequestSecurityCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr bool
+	}{
+		{"sent", 200, `{"code":200,"message":"Email sent"}`, false},
+		{"api error", 422, `{"error":{"code":422,"error_ref":13009,"message":"invalid email"}}`, true},
+		{"undecodable", 502, `<html>Bad Gateway</html>`, true},
+	}
+	for _, tt := range tests {
+		user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+			r.ParseForm()
+			if r.URL.Path != "/v1/oauth/emailrequest" || r.PostForm.Get("email") != "player@example.com" {
+				t.Errorf("%s: unexpected request %s %v", tt.name, r.URL.Path, r.PostForm)
+			}
+			w.WriteHeader(tt.status)
+			w.Write([]byte(tt.body))
+		})
+		m, err := user.RequestSecurityCode(context.Background())
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if !tt.wantErr && (m == nil || m.Message != "Email sent") {
+			t.Errorf("%s: message = %+v", tt.name, m)
+		}
+	}
+}
+
+func TestExchangeSecurityCode(t *testing.T) {
+	expires := time.Now().Add(24 * time.Hour).Unix()
+	var dateExpires string
+	user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		dateExpires = r.PostForm.Get("date_expires")
+		if r.PostForm.Get("security_code") != "ABCDE" {
+			w.WriteHeader(401)
+			w.Write([]byte(`{"error":{"code":401,"error_ref":11012,"message":"invalid security code"}}`))
+			return
+		}
+		w.Write([]byte(`{"code":200,"access_token":"token","date_expires":` + strconv.FormatInt(expires, 10) + `}`))
+	})
+	store := NewMemoryTokenStore()
+	err := user.UseTokenStore(store, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = user.ExchangeSecurityCode(context.Background(), "WRONG", 0)
+	if err == nil || user.OAuth2Token() != "" {
+		t.Fatalf("wrong code: error = %v, token = %q", err, user.OAuth2Token())
+	}
+	if dateExpires != "" {
+		t.Errorf("zero lifetime sent date_expires=%s", dateExpires)
+	}
+
+	_, err = user.ExchangeSecurityCode(context.Background(), "ABCDE", 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent, _ := strconv.ParseInt(dateExpires, 10, 64)
+	if d := sent - expires; d < -5 || d > 5 {
+		t.Errorf("date_expires = %s, want about %d", dateExpires, expires)
+	}
+	if user.OAuth2Token() != "token" || user.TokenExpires().Unix() != expires {
+		t.Errorf("token = %q expiring %v", user.OAuth2Token(), user.TokenExpires())
+	}
+	saved, _ := store.LoadToken("player@example.com", 1)
+	if saved == nil || saved.AccessToken != "token" {
+		t.Errorf("stored token = %+v", saved)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/oauth/logout" || r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"code":200,"message":"Logged out"}`))
+	})
+	store := NewMemoryTokenStore()
+	store.SaveToken("player@example.com", 1, &Token{AccessToken: "token"})
+	err := user.UseTokenStore(store, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = user.Logout(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.OAuth2Token() != "" {
+		t.Error("token kept after logout")
+	}
+	if saved, _ := store.LoadToken("player@example.com", 1); saved != nil {
+		t.Errorf("stored token kept after logout: %+v", saved)
+	}
+}
+
+func TestAuthCancelledContext(t *testing.T) {
+	user := newTestUser(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent with a cancelled context")
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := user.RequestSecurityCode(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
+
+func TestUseTokenStoreExpired(t *testing.T) {
+	store := NewFileTokenStore(filepath.Join(t.TempDir(), "tokens"))
+	err := store.SaveToken("player@example.com", 1, &Token{AccessToken: "old", DateExpires: NewTimestamp(time.Now().Add(-time.Minute))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := NewUser("key", "player@example.com")
+	if err := user.UseTokenStore(store, 1); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("error = %v, want ErrTokenExpired", err)
+	}
+	if user.OAuth2Token() != "" {
+		t.Error("expired token loaded")
+	}
+	if err := user.UseTokenStore(store, 2); err != nil || user.OAuth2Token() != "" {
+		t.Errorf("missing token: error = %v, token = %q", err, user.OAuth2Token())
+	}
+}
+
+func TestWritesRequireUnexpiredToken(t *testing.T) {
+	user := NewUser("key", "")
+	user.SetOAuth2Token("token")
+	user.SetTokenExpires(time.Now().Add(-time.Hour))
+	user.transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		t.Errorf("%s %s sent with an expired token", req.Method, req.URL.Path)
+		return nil, errors.New("unexpected request")
+	})
+	writes := map[string]func() error{
+		"DeleteMod": func() error { return user.DeleteMod(2, 1) },
+		"DeleteModMedia": func() error {
+			return user.DeleteModMedia(2, 1, &DeleteModMediaOptions{Images: []string{"a.png"}})
+		},
+		"AddModMedia": func() error {
+			_, err := user.AddModMedia(2, 1, &AddModMediaOptions{Logo: "logo.png"})
+			return err
+		},
+		"AddGameMedia": func() error {
+			_, err := user.AddGameMedia("logo.png", "icon.png", "header.png", 1)
+			return err
+		},
+		"DeleteModMetadata": func() error { return user.DeleteModMetadata([]string{"a:b"}, 2, 1) },
+		"AddModMetadata": func() error {
+			_, err := user.AddModMetadata([]string{"a:b"}, 2, 1)
+			return err
+		},
+		"AddModComment": func() error {
+			_, err := user.AddModComment("hello", 2, 1, nil)
+			return err
+		},
+		"EditMod": func() error {
+			_, err := user.EditMod(2, 1, &EditModOptions{Name: String("a")})
+			return err
+		},
+		"DeleteModComment": func() error { return DeleteModComment(3, 2, 1, user) },
+	}
+	for name, write := range writes {
+		if err := write(); !errors.Is(err, ErrTokenExpired) {
+			t.Errorf("%s error = %v, want ErrTokenExpired", name, err)
+		}
+	}
+}
+
+func TestWithLanguage(t *testing.T) {
+	user := NewUser("key", "")
+	user.SetLanguage("en")
+	hooks := make([]Hook, 1, 4)
+	hooks[0] = HookFuncs{}
+	user.hooks = hooks
+
+	ja := user.WithLanguage("ja")
+	if ja.Language() != "ja" || user.Language() != "en" {
+		t.Errorf("languages = %q, %q, want ja, en", ja.Language(), user.Language())
+	}
+	req, _ := ja.newRequest("GET", "https://api.mod.io/v1/games/1", nil)
+	if got := req.Header.Get("Accept-Language"); got != "ja" {
+		t.Errorf("copy sent Accept-Language %q", got)
+	}
+	req, _ = user.newRequest("GET", "https://api.mod.io/v1/games/1", nil)
+	if got := req.Header.Get("Accept-Language"); got != "en" {
+		t.Errorf("original sent Accept-Language %q", got)
+	}
+
+	var fromCopy, fromOriginal bool
+	ja.AddHook(HookFuncs{BeforeRequestFunc: func(*RequestEvent) { fromCopy = true }})
+	user.AddHook(HookFuncs{BeforeRequestFunc: func(*RequestEvent) { fromOriginal = true }})
+	ja.runHooks(Hook.BeforeRequest, &RequestEvent{})
+	if !fromCopy || fromOriginal {
+		t.Errorf("copy ran its hook %v and the original's %v", fromCopy, fromOriginal)
+	}
+	if len(user.hooks) != 2 || len(ja.hooks) != 2 {
+		t.Errorf("hooks = %d and %d, want 2 each", len(user.hooks), len(ja.hooks))
+	}
+}